@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/git"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+	"github.com/spf13/cobra"
+)
+
+var blameCmd = &cobra.Command{
+	Use:   "blame <slug>",
+	Short: "Show who last changed each part of a note",
+	Long: `Show the commit, author, and date that last changed each section of a
+note's content. Consecutive lines from the same commit are grouped together.
+Line numbers refer to the note content (after the frontmatter); file line
+numbers are included as well.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBlame,
+}
+
+type blameHunk struct {
+	git.BlameHunk
+	FileStartLine int  `json:"file_start_line"`
+	FileEndLine   int  `json:"file_end_line"`
+	Uncommitted   bool `json:"uncommitted"`
+}
+
+type blameResult struct {
+	Slug     string      `json:"slug"`
+	FilePath string      `json:"file_path"`
+	Hunks    []blameHunk `json:"hunks"`
+}
+
+func init() {
+	rootCmd.AddCommand(blameCmd)
+}
+
+func runBlame(cmd *cobra.Command, args []string) error {
+	note, err := notes.GetNote(args[0])
+	if err != nil {
+		return fmt.Errorf("could not find note: %w", err)
+	}
+
+	data, err := os.ReadFile(note.FilePath)
+	if err != nil {
+		return fmt.Errorf("could not read note: %w", err)
+	}
+
+	relPath, err := filepath.Rel(config.Get().NotesDir, note.FilePath)
+	if err != nil {
+		return fmt.Errorf("could not get relative path: %w", err)
+	}
+
+	lines, err := git.Blame(relPath)
+	if errors.Is(err, git.ErrNotCommitted) {
+		return fmt.Errorf("%s is not committed yet, so there is nothing to blame", note.Slug)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Map file lines to content lines, dropping the frontmatter. Both
+	// backends blame the working copy, so its frontmatter gives the offset.
+	offset := frontmatter.ContentLine(data) - 1
+	var contentLines []git.BlameLine
+	for _, line := range lines {
+		if line.Line > offset {
+			line.Line -= offset
+			contentLines = append(contentLines, line)
+		}
+	}
+
+	result := blameResult{
+		Slug:     note.Slug,
+		FilePath: note.FilePath,
+		Hunks:    []blameHunk{},
+	}
+	for _, h := range git.GroupBlame(contentLines) {
+		result.Hunks = append(result.Hunks, blameHunk{
+			BlameHunk:     h,
+			FileStartLine: h.StartLine + offset,
+			FileEndLine:   h.EndLine + offset,
+			Uncommitted:   h.Uncommitted(),
+		})
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if quietOutput {
+		for _, h := range result.Hunks {
+			fmt.Printf("%d-%d %s\n", h.StartLine, h.EndLine, shortHash(h.Hash))
+		}
+		return nil
+	}
+
+	// Human-readable output
+	if len(result.Hunks) == 0 {
+		fmt.Println("No content to blame")
+		return nil
+	}
+
+	fmt.Printf("Blame for %s:\n\n", note.Title)
+	for _, h := range result.Hunks {
+		lineRange := fmt.Sprintf("%d", h.StartLine)
+		if h.EndLine != h.StartLine {
+			lineRange = fmt.Sprintf("%d-%d", h.StartLine, h.EndLine)
+		}
+
+		if h.Uncommitted {
+			fmt.Printf("  lines %-9s (not committed yet)\n", lineRange)
+			continue
+		}
+
+		fmt.Printf("  lines %-9s %s  %s  %s\n", lineRange, shortHash(h.Hash), h.Date.Format("2006-01-02"), h.Author)
+		fmt.Printf("  %-15s %s\n", "", h.Summary)
+	}
+
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
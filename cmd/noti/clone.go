@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/git"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/spf13/cobra"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <url> [directory]",
+	Short: "Clone a notes repository",
+	Long: `Clone an existing notes repository and use it as the notes directory.
+The url can be a remote URL, a local path, or a file:// URL.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runClone,
+}
+
+var cloneForce bool
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+	cloneCmd.Flags().BoolVar(&cloneForce, "force", false, "use the clone even if it contains no notes")
+}
+
+func runClone(cmd *cobra.Command, args []string) error {
+	url := args[0]
+
+	var dir string
+	if len(args) > 1 {
+		dir = args[1]
+	} else {
+		dir = cloneDirName(url)
+	}
+
+	absPath, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("could not get absolute path: %w", err)
+	}
+
+	// The notes directory is saved in the config, so it has to be loadable
+	if configErr != nil {
+		return fmt.Errorf("could not load config %s (fix or remove it, then clone again): %w", config.File(), configErr)
+	}
+
+	existed := true
+	if info, err := os.Stat(absPath); os.IsNotExist(err) {
+		existed = false
+	} else if err != nil {
+		return fmt.Errorf("could not check destination: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("destination %s already exists and is not a directory", absPath)
+	} else if entries, err := os.ReadDir(absPath); err != nil {
+		return fmt.Errorf("could not read destination: %w", err)
+	} else if len(entries) > 0 {
+		return fmt.Errorf("destination %s already exists and is not empty", absPath)
+	}
+
+	if err := git.Clone(url, absPath); err != nil {
+		removeClone(absPath, existed)
+		return err
+	}
+
+	// Point the config at the clone and make sure it holds notes
+	cfg := config.Get()
+	previous := cfg.NotesDir
+	cfg.NotesDir = absPath
+
+	found, err := notes.ListNotes("", "")
+	if err != nil {
+		cfg.NotesDir = previous
+		removeClone(absPath, existed)
+		return fmt.Errorf("could not read cloned notes: %w", err)
+	}
+
+	if len(found) == 0 && !cloneForce {
+		cfg.NotesDir = previous
+		removeClone(absPath, existed)
+		return fmt.Errorf("no notes found in %s (use --force to clone it anyway)", url)
+	}
+
+	if err := config.Save(); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	fmt.Printf("Cloned %s into %s (%d note%s)\n", url, absPath, len(found), plural(len(found)))
+	fmt.Printf("\nNotes directory set in: %s\n", config.File())
+
+	return nil
+}
+
+// removeClone deletes a clone that is not going to be used. A destination
+// directory that existed (empty) before the clone is emptied but kept, so
+// only what the clone created is removed.
+func removeClone(dir string, existed bool) {
+	if !existed {
+		os.RemoveAll(dir)
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		os.RemoveAll(filepath.Join(dir, entry.Name()))
+	}
+}
+
+// cloneDirName derives a directory name from a repository url like git does
+func cloneDirName(url string) string {
+	name := strings.TrimRight(url, "/")
+	name = strings.TrimSuffix(name, "/.git")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, ".git")
+
+	if name == "" {
+		return "notes"
+	}
+	return name
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/devjasha/noti-vim/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the notes setup for problems",
+	Long: `Check that the config loads, the notes directory is usable, git is
+available and local remotes are reachable, and that notes have valid
+frontmatter, unique titles, file names matching their titles, and slugs
+that do not collide on case-insensitive file systems.
+
+Network remotes are listed but not contacted.`,
+	Args:          cobra.NoArgs,
+	RunE:          runDoctor,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	report := doctor.Run(configErr)
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, check := range report.Checks {
+			if quietOutput && check.Status == doctor.StatusOK {
+				continue
+			}
+			fmt.Printf("[%-4s] %-17s %s\n", check.Status, check.Name, check.Message)
+			for _, detail := range check.Details {
+				fmt.Printf("         - %s\n", detail)
+			}
+		}
+
+		if !quietOutput {
+			fmt.Printf("\n%d ok, %d warning(s), %d failure(s)\n", report.Summary.OK, report.Summary.Warn, report.Summary.Fail)
+		}
+	}
+
+	if !report.Healthy() {
+		return fmt.Errorf("%d check(s) failed", report.Summary.Fail)
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/diff"
+	"github.com/devjasha/noti-vim/internal/dupes"
+	"github.com/devjasha/noti-vim/internal/git"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/spf13/cobra"
+)
+
+var dupesCmd = &cobra.Command{
+	Use:   "dupes",
+	Short: "Find duplicate and near-duplicate notes",
+	Long: `Find notes with the same content, and notes that are nearly the same.
+
+Notes are exact duplicates when their content, ignoring frontmatter and
+trailing whitespace, is identical. Near duplicates share most of their runs
+of three consecutive words: --threshold sets the share (Jaccard similarity)
+they must reach, from 0 to 1. Candidates are found with MinHash, so large
+vaults are not compared pair by pair.
+
+Each group lists its notes oldest first, with a diff of every note against
+the first one. With --merge, you pick the note to keep in each group and the
+others are deleted. The kept note gains their tags, and lines it does not
+have are appended to it under a "Merged from" heading, so nothing is lost.
+Their slugs, file names, ids, titles and aliases become its aliases, so
+wikilinks to them keep resolving; Markdown links to their files do not.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDupes,
+}
+
+var (
+	dupesThreshold float64
+	dupesPreview   int
+	dupesMerge     bool
+	dupesNoCommit  bool
+)
+
+func init() {
+	rootCmd.AddCommand(dupesCmd)
+	dupesCmd.Flags().Float64Var(&dupesThreshold, "threshold", dupes.DefaultThreshold, "similarity from 0 to 1 at which notes are near duplicates")
+	dupesCmd.Flags().IntVar(&dupesPreview, "preview", 12, "diff lines to show per note (0 to hide diffs)")
+	dupesCmd.Flags().BoolVar(&dupesMerge, "merge", false, "interactively merge each group into one note")
+	dupesCmd.Flags().BoolVar(&dupesNoCommit, "no-commit", false, "do not commit merges to git")
+}
+
+// dupeGroup is the JSON form of a group of duplicates
+type dupeGroup struct {
+	Exact      bool        `json:"exact"`
+	Similarity float64     `json:"similarity"`
+	Notes      []dupeEntry `json:"notes"`
+}
+
+type dupeEntry struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+func runDupes(cmd *cobra.Command, args []string) error {
+	if dupesThreshold <= 0 || dupesThreshold > 1 {
+		return fmt.Errorf("threshold must be above 0 and at most 1")
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+	if dupesMerge && (jsonOutput || quietOutput) {
+		return fmt.Errorf("--merge cannot be combined with --json or --quiet")
+	}
+
+	root := config.Get().NotesDir
+	docs, err := notes.LoadDocuments(root)
+	if err != nil {
+		return fmt.Errorf("could not load notes: %w", err)
+	}
+
+	groups := dupes.Find(docs, dupesThreshold)
+
+	if jsonOutput {
+		out := make([]dupeGroup, 0, len(groups))
+		for _, g := range groups {
+			group := dupeGroup{Exact: g.Exact, Similarity: g.Similarity}
+			for _, doc := range g.Docs {
+				group.Notes = append(group.Notes, dupeEntry{Slug: doc.Slug(), Title: docTitle(doc)})
+			}
+			out = append(out, group)
+		}
+
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if quietOutput {
+		for _, g := range groups {
+			slugs := make([]string, len(g.Docs))
+			for i, doc := range g.Docs {
+				slugs[i] = doc.Slug()
+			}
+			fmt.Println(strings.Join(slugs, " "))
+		}
+		return nil
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("No duplicate notes found")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var paths []string
+	merged := 0
+
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		printDupeGroup(i+1, g)
+
+		if !dupesMerge {
+			continue
+		}
+
+		keep, quit := chooseKeep(reader, len(g.Docs))
+		if quit {
+			break
+		}
+		if keep == nil {
+			continue
+		}
+
+		changed, err := mergeDupes(root, g.Docs[*keep], g.Docs)
+		paths = append(paths, changed...)
+		if err != nil {
+			return err
+		}
+		merged += len(g.Docs) - 1
+	}
+
+	if !dupesMerge {
+		fmt.Printf("\nFound %d group(s) of duplicates\n", len(groups))
+		return nil
+	}
+
+	if merged == 0 {
+		fmt.Println("\nNothing merged")
+		return nil
+	}
+
+	if !dupesNoCommit && git.IsGitRepo() {
+		message := fmt.Sprintf("Merge %d duplicate note%s", merged, plural(merged))
+		if err := git.Commit(message, paths...); err != nil {
+			return fmt.Errorf("notes merged but commit failed: %w", err)
+		}
+	}
+
+	fmt.Printf("\nMerged %d duplicate note%s\n", merged, plural(merged))
+	return nil
+}
+
+func printDupeGroup(n int, g dupes.Group) {
+	if g.Exact {
+		fmt.Printf("Group %d: exact duplicates\n", n)
+	} else {
+		fmt.Printf("Group %d: %.0f%% similar\n", n, g.Similarity*100)
+	}
+
+	for i, doc := range g.Docs {
+		fmt.Printf("  %d. %s (%s)\n", i+1, docTitle(doc), doc.Slug())
+	}
+
+	if g.Exact || dupesPreview <= 0 {
+		return
+	}
+
+	first := g.Docs[0]
+	for _, doc := range g.Docs[1:] {
+		d := diff.Unified(first.Path, doc.Path, first.Content, doc.Content, 1)
+		if d == "" {
+			fmt.Printf("\n    %s is identical to %s\n", doc.Path, first.Path)
+			continue
+		}
+
+		lines := strings.Split(strings.TrimSuffix(d, "\n"), "\n")
+		fmt.Println()
+		for i, line := range lines {
+			if i == dupesPreview {
+				fmt.Printf("    … %d more line%s\n", len(lines)-i, plural(len(lines)-i))
+				break
+			}
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
+
+// chooseKeep asks which note of a group to keep. It returns nil to skip the
+// group, and quit when merging should stop.
+func chooseKeep(reader *bufio.Reader, count int) (keep *int, quit bool) {
+	for {
+		fmt.Fprintf(os.Stderr, "Keep which note? [1-%d, s=skip, q=quit] ", count)
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && answer == "" {
+			fmt.Fprintln(os.Stderr)
+			return nil, true
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		switch answer {
+		case "s", "skip", "":
+			return nil, false
+		case "q", "quit":
+			return nil, true
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= count {
+			index := n - 1
+			return &index, false
+		}
+		fmt.Fprintf(os.Stderr, "Please answer a number from 1 to %d, s or q\n", count)
+	}
+}
+
+// mergeDupes merges the group into keep, writes it and deletes the other
+// notes. It returns the paths it changed, for committing.
+func mergeDupes(root string, keep *notes.Document, group []*notes.Document) ([]string, error) {
+	var paths []string
+
+	appended := dupes.Merge(keep, group)
+	data, err := keep.Render()
+	if err != nil {
+		return paths, fmt.Errorf("could not format %s: %w", keep.Path, err)
+	}
+	if string(data) != string(keep.Data) {
+		if err := notes.WriteAtomic(filepath.Join(root, filepath.FromSlash(keep.Path)), data); err != nil {
+			return paths, fmt.Errorf("could not write %s: %w", keep.Path, err)
+		}
+		paths = append(paths, keep.Path)
+	}
+
+	for _, doc := range group {
+		if doc == keep {
+			continue
+		}
+		if err := notes.DeleteNote(doc.Slug()); err != nil {
+			return paths, fmt.Errorf("could not delete %s: %w", doc.Path, err)
+		}
+		paths = append(paths, doc.Path)
+		if n := appended[doc.Slug()]; n > 0 {
+			fmt.Printf("Merged %s into %s, appending %d line%s\n", doc.Slug(), keep.Slug(), n, plural(n))
+		} else {
+			fmt.Printf("Merged %s into %s\n", doc.Slug(), keep.Slug())
+		}
+	}
+
+	return paths, nil
+}
+
+// docTitle returns the title of a document, or its slug if it has none
+func docTitle(doc *notes.Document) string {
+	if doc.Frontmatter != nil && doc.Frontmatter.Title != "" {
+		return doc.Frontmatter.Title
+	}
+	return doc.Slug()
+}
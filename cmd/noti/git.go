@@ -1,7 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/devjasha/noti-vim/internal/git"
 	"github.com/spf13/cobra"
@@ -63,7 +67,71 @@ var gitLogCmd = &cobra.Command{
 	RunE:  runGitLog,
 }
 
+var gitDiffCmd = &cobra.Command{
+	Use:   "diff [paths...]",
+	Short: "Show unstaged changes",
+	Long:  `Show a unified diff of changes that have not been committed yet`,
+	RunE:  runGitDiff,
+}
+
+var gitRemoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Manage git remotes",
+	Long:  `Add, remove, or list the remotes of the notes repository`,
+}
+
+var gitRemoteAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Add a remote",
+	Long:  `Add a remote repository (URL, local path, or file:// URL)`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  runGitRemoteAdd,
+}
+
+var gitRemoteRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a remote",
+	Long:    `Remove a remote from the notes repository`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runGitRemoteRemove,
+}
+
+var gitRemoteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List remotes",
+	Long:  `List the remotes of the notes repository`,
+	RunE:  runGitRemoteList,
+}
+
+var gitHooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks",
+	Long:  `Install or remove git hooks that validate notes before committing`,
+}
+
+var gitHooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the pre-commit hook",
+	Long: `Install a pre-commit hook that runs 'noti lint --staged' and blocks commits with problems.
+
+The hook uses the config given with --config, if any. Commits made by noti
+with git_backend: go do not run hooks, so they are not checked.`,
+	RunE: runGitHooksInstall,
+}
+
+var gitHooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the pre-commit hook",
+	Long:  `Remove the pre-commit hook installed by noti`,
+	RunE:  runGitHooksUninstall,
+}
+
+// hookMarker identifies hooks written by noti
+const hookMarker = "# Installed by noti"
+
 var (
+	hooksForce  bool
 	syncMessage string
 	logLimit    int
 )
@@ -78,6 +146,17 @@ func init() {
 	gitCmd.AddCommand(gitPullCmd)
 	gitCmd.AddCommand(gitSyncCmd)
 	gitCmd.AddCommand(gitLogCmd)
+	gitCmd.AddCommand(gitDiffCmd)
+	gitCmd.AddCommand(gitRemoteCmd)
+	gitCmd.AddCommand(gitHooksCmd)
+
+	gitHooksCmd.AddCommand(gitHooksInstallCmd)
+	gitHooksCmd.AddCommand(gitHooksUninstallCmd)
+	gitHooksInstallCmd.Flags().BoolVar(&hooksForce, "force", false, "overwrite an existing pre-commit hook")
+
+	gitRemoteCmd.AddCommand(gitRemoteAddCmd)
+	gitRemoteCmd.AddCommand(gitRemoteRemoveCmd)
+	gitRemoteCmd.AddCommand(gitRemoteListCmd)
 
 	gitSyncCmd.Flags().StringVarP(&syncMessage, "message", "m", "", "commit message for sync")
 	gitLogCmd.Flags().IntVarP(&logLimit, "limit", "n", 10, "number of commits to show")
@@ -188,3 +267,154 @@ func runGitLog(cmd *cobra.Command, args []string) error {
 	fmt.Print(log)
 	return nil
 }
+
+func runGitDiff(cmd *cobra.Command, args []string) error {
+	diff, err := git.Diff(args...)
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		fmt.Println("No unstaged changes")
+		return nil
+	}
+
+	fmt.Print(diff)
+	return nil
+}
+
+func runGitRemoteAdd(cmd *cobra.Command, args []string) error {
+	name, url := args[0], args[1]
+
+	if err := git.AddRemote(name, url); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added remote %s: %s\n", name, url)
+	return nil
+}
+
+func runGitRemoteRemove(cmd *cobra.Command, args []string) error {
+	if err := git.RemoveRemote(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed remote %s\n", args[0])
+	return nil
+}
+
+func runGitRemoteList(cmd *cobra.Command, args []string) error {
+	remotes, err := git.Remotes()
+	if err != nil {
+		return err
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		if remotes == nil {
+			remotes = []git.Remote{}
+		}
+		data, err := json.MarshalIndent(remotes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if quietOutput {
+		for _, remote := range remotes {
+			fmt.Println(remote.Name)
+		}
+		return nil
+	}
+
+	if len(remotes) == 0 {
+		fmt.Println("No remotes configured")
+		return nil
+	}
+
+	for _, remote := range remotes {
+		fmt.Printf("  %-12s %s\n", remote.Name, remote.URL)
+	}
+
+	return nil
+}
+
+func runGitHooksInstall(cmd *cobra.Command, args []string) error {
+	hooksDir, err := git.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+	if existing, err := os.ReadFile(hookPath); err == nil {
+		if !strings.Contains(string(existing), hookMarker) && !hooksForce {
+			return fmt.Errorf("a pre-commit hook already exists at %s (use --force to replace it)", hookPath)
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "noti"
+	}
+
+	command := []string{shellQuote(filepath.ToSlash(exe))}
+	if cfgFile != "" {
+		// Lint the vault of the config the hook was installed with
+		absConfig, err := filepath.Abs(cfgFile)
+		if err != nil {
+			return fmt.Errorf("could not get absolute path: %w", err)
+		}
+		command = append(command, "--config", shellQuote(filepath.ToSlash(absConfig)))
+	}
+	command = append(command, "lint", "--staged")
+
+	script := fmt.Sprintf("#!/bin/sh\n%s\nexec %s\n", hookMarker, strings.Join(command, " "))
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("could not create hooks directory: %w", err)
+	}
+
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		return fmt.Errorf("could not write pre-commit hook: %w", err)
+	}
+
+	fmt.Printf("Installed pre-commit hook: %s\n", hookPath)
+	return nil
+}
+
+// shellQuote quotes s as a single word for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func runGitHooksUninstall(cmd *cobra.Command, args []string) error {
+	hooksDir, err := git.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+	existing, err := os.ReadFile(hookPath)
+	if os.IsNotExist(err) {
+		fmt.Println("No pre-commit hook installed")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read pre-commit hook: %w", err)
+	}
+
+	if !strings.Contains(string(existing), hookMarker) {
+		return fmt.Errorf("pre-commit hook at %s was not installed by noti", hookPath)
+	}
+
+	if err := os.Remove(hookPath); err != nil {
+		return fmt.Errorf("could not remove pre-commit hook: %w", err)
+	}
+
+	fmt.Println("Removed pre-commit hook")
+	return nil
+}
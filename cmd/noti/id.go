@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/noteid"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/spf13/cobra"
+)
+
+var idCmd = &cobra.Command{
+	Use:   "id",
+	Short: "Manage stable note IDs",
+	Long: `Notes can carry an id in their frontmatter that stays the same when the
+note is renamed or moved. Links and lookups by slug fall back to the id.
+
+Set id_format to "timestamp" or "ulid" in the config to give new notes an id.`,
+}
+
+var idAssignCmd = &cobra.Command{
+	Use:   "assign [slugs...]",
+	Short: "Add IDs to notes that have none",
+	Long: `Add an id to every note (or the given notes) that does not have one yet.
+Timestamp IDs are derived from the note's created date. Other frontmatter
+fields are preserved.`,
+	RunE: runIDAssign,
+}
+
+var (
+	idFormat string
+	idDryRun bool
+)
+
+type idAssignment struct {
+	Slug string `json:"slug"`
+	ID   string `json:"id"`
+}
+
+func init() {
+	rootCmd.AddCommand(idCmd)
+	idCmd.AddCommand(idAssignCmd)
+	idAssignCmd.Flags().StringVar(&idFormat, "format", "", "id format: timestamp or ulid (default from config, else timestamp)")
+	idAssignCmd.Flags().BoolVarP(&idDryRun, "dry-run", "n", false, "show the ids that would be assigned without writing")
+}
+
+func runIDAssign(cmd *cobra.Command, args []string) error {
+	format := idFormat
+	if format == "" {
+		format = config.Get().IDFormat
+	}
+	if format == noteid.FormatNone {
+		format = noteid.FormatTimestamp
+	}
+	if err := noteid.Validate(format); err != nil {
+		return err
+	}
+
+	root := config.Get().NotesDir
+
+	var docs []*notes.Document
+	var err error
+	if len(args) == 0 {
+		docs, err = notes.LoadDocuments(root)
+	} else {
+		for _, slug := range args {
+			doc, loadErr := notes.LoadDocument(root, slug+".md")
+			if loadErr != nil {
+				return loadErr
+			}
+			docs = append(docs, doc)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	taken, err := notes.UsedIDs()
+	if err != nil {
+		return fmt.Errorf("could not list notes: %w", err)
+	}
+
+	assigned := []idAssignment{}
+	for _, doc := range docs {
+		slug := doc.Slug()
+
+		if doc.ParseErr != nil || doc.Frontmatter == nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s: no valid frontmatter\n", slug)
+			continue
+		}
+		if doc.Frontmatter.ID != "" {
+			continue
+		}
+
+		created := doc.Frontmatter.Created
+		if created.IsZero() {
+			created = doc.ModTime
+		}
+
+		id, err := noteid.New(format, created, func(id string) bool { return taken[id] })
+		if err != nil {
+			return err
+		}
+		taken[id] = true
+		doc.Frontmatter.ID = id
+
+		if !idDryRun {
+			data, err := doc.Render()
+			if err != nil {
+				return fmt.Errorf("could not format %s: %w", slug, err)
+			}
+			if err := notes.WriteAtomic(filepath.Join(root, filepath.FromSlash(doc.Path)), data); err != nil {
+				return fmt.Errorf("could not write %s: %w", slug, err)
+			}
+		}
+
+		assigned = append(assigned, idAssignment{Slug: slug, ID: id})
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(assigned, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, a := range assigned {
+		fmt.Printf("%s  %s\n", a.ID, a.Slug)
+	}
+
+	if !quietOutput {
+		verb := "Assigned"
+		if idDryRun {
+			verb = "Would assign"
+		}
+		fmt.Printf("%s %d id(s)\n", verb, len(assigned))
+	}
+
+	return nil
+}
@@ -54,7 +54,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Initialized notes directory at: %s\n", absPath)
-	fmt.Println("\nConfiguration saved to: ~/.config/noti/config.yaml")
+	fmt.Printf("\nConfiguration saved to: %s\n", config.File())
 	fmt.Println("\nYou can now create notes with: noti new \"My First Note\"")
 
 	return nil
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/git"
+	"github.com/devjasha/noti-vim/internal/links"
+	"github.com/devjasha/noti-vim/internal/lint"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/vocab"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [slugs...]",
+	Short: "Check notes for problems",
+	Long: `Check notes for problems such as invalid frontmatter, missing titles,
+duplicate tags, and broken links. Lints all notes unless slugs are given.
+With --staged, only notes staged for the next git commit are checked, using
+their staged content.
+
+Rules can be switched off in .noti.yaml in the notes directory:
+
+  lint:
+    rules:
+      trailing-whitespace: false
+
+Use --rules to list all rules.`,
+	RunE:          runLint,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+var (
+	lintStaged    bool
+	lintFix       bool
+	lintQuickfix  bool
+	lintListRules bool
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolVar(&lintStaged, "staged", false, "only lint notes staged for commit")
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "automatically fix problems where possible")
+	lintCmd.Flags().BoolVar(&lintQuickfix, "quickfix", false, "print file:line:col: message with absolute paths for Vim")
+	lintCmd.Flags().BoolVar(&lintListRules, "rules", false, "list available rules")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	vault, err := config.LoadVault()
+	if err != nil {
+		return err
+	}
+
+	rules, err := lint.Enabled(lint.Rules(), vault.Lint.Rules)
+	if err != nil {
+		return fmt.Errorf("invalid lint configuration in %s: %w", config.VaultFile, err)
+	}
+
+	if lintListRules {
+		printLintRules(rules)
+		return nil
+	}
+
+	if lintFix && lintStaged {
+		return fmt.Errorf("--fix cannot be combined with --staged")
+	}
+
+	docs, err := lintDocuments(args)
+	if err != nil {
+		return err
+	}
+
+	allNotes, err := notes.ListNotes("", "")
+	if err != nil {
+		return fmt.Errorf("could not list notes: %w", err)
+	}
+
+	vocabulary, err := vocab.Load()
+	if err != nil {
+		return err
+	}
+
+	linter := lint.New(rules, &lint.Context{
+		Links:      links.NewResolver(allNotes),
+		Vocabulary: vocabulary,
+	})
+
+	fixed := 0
+	if lintFix {
+		for _, doc := range docs {
+			if !linter.Fix(doc) {
+				continue
+			}
+			if err := writeLintFix(doc); err != nil {
+				return err
+			}
+			fixed++
+		}
+	}
+
+	diags := linter.Lint(docs)
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		if diags == nil {
+			diags = []lint.Diagnostic{}
+		}
+		data, err := json.MarshalIndent(diags, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		root := config.Get().NotesDir
+		for _, d := range diags {
+			if lintQuickfix {
+				fmt.Printf("%s:%d:%d: %s [%s]\n", filepath.Join(root, filepath.FromSlash(d.File)), d.Line, d.Col, d.Message, d.Rule)
+			} else {
+				fmt.Println(d.String())
+			}
+		}
+
+		if !quietOutput && !lintQuickfix {
+			if fixed > 0 {
+				fmt.Printf("Fixed %d note(s)\n", fixed)
+			}
+			if len(diags) == 0 {
+				fmt.Printf("No problems found in %d note(s)\n", len(docs))
+			}
+		}
+	}
+
+	if len(diags) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(diags))
+	}
+
+	return nil
+}
+
+// writeLintFix saves a fixed document and reloads it so diagnostics refer
+// to the new content
+func writeLintFix(doc *notes.Document) error {
+	data, err := doc.Render()
+	if err != nil {
+		return fmt.Errorf("could not format %s: %w", doc.Path, err)
+	}
+
+	path := filepath.Join(config.Get().NotesDir, filepath.FromSlash(doc.Path))
+	if err := notes.WriteAtomic(path, data); err != nil {
+		return fmt.Errorf("could not write %s: %w", doc.Path, err)
+	}
+
+	modTime := doc.ModTime
+	*doc = *notes.NewDocument(doc.Path, data)
+	doc.ModTime = modTime
+	return nil
+}
+
+func printLintRules(enabled []lint.Rule) {
+	on := make(map[string]bool)
+	for _, rule := range enabled {
+		on[rule.Name()] = true
+	}
+
+	for _, rule := range lint.Rules() {
+		state := "off"
+		if on[rule.Name()] {
+			state = "on"
+		}
+		fixable := ""
+		if _, ok := rule.(lint.Fixer); ok {
+			fixable = " (fixable)"
+		}
+		fmt.Printf("  %-20s %-3s  %s%s\n", rule.Name(), state, rule.Description(), fixable)
+	}
+}
+
+// lintDocuments loads the notes selected by the arguments and flags
+func lintDocuments(slugs []string) ([]*notes.Document, error) {
+	root := config.Get().NotesDir
+
+	if lintStaged {
+		files, err := git.StagedFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		var docs []*notes.Document
+		for _, file := range files {
+			if !notes.IsNote(file) {
+				continue
+			}
+			data, err := git.StagedContent(file)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, notes.NewDocument(file, data))
+		}
+		return docs, nil
+	}
+
+	if len(slugs) == 0 {
+		return notes.LoadDocuments(root)
+	}
+
+	var docs []*notes.Document
+	for _, slug := range slugs {
+		doc, err := notes.LoadDocument(root, slug+".md")
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/devjasha/noti-vim/internal/notes"
 	"github.com/spf13/cobra"
@@ -16,18 +17,24 @@ var listCmd = &cobra.Command{
 }
 
 var (
-	listFolder string
-	listTag    string
+	listFolder   string
+	listTag      string
+	listExactTag bool
 )
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().StringVarP(&listFolder, "folder", "f", "", "filter by folder")
-	listCmd.Flags().StringVarP(&listTag, "tag", "t", "", "filter by tag")
+	listCmd.Flags().StringVarP(&listTag, "tag", "t", "", "filter by tag (includes nested tags such as tag/sub)")
+	listCmd.Flags().BoolVar(&listExactTag, "exact", false, "match the tag exactly, without nested tags")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	notesList, err := notes.ListNotes(listFolder, listTag)
+	notesList, err := notes.ListNotesFiltered(notes.Filter{
+		Folder:   listFolder,
+		Tag:      listTag,
+		ExactTag: listExactTag,
+	})
 	if err != nil {
 		return fmt.Errorf("could not list notes: %w", err)
 	}
@@ -64,6 +71,9 @@ func runList(cmd *cobra.Command, args []string) error {
 		if len(note.Tags) > 0 {
 			fmt.Printf("    tags: %v\n", note.Tags)
 		}
+		if len(note.InlineTags) > 0 {
+			fmt.Printf("    inline tags: #%s\n", strings.Join(note.InlineTags, " #"))
+		}
 		if note.Folder != "" {
 			fmt.Printf("    folder: %s\n", note.Folder)
 		}
@@ -11,6 +11,8 @@ import (
 var (
 	version = "0.1.0"
 	cfgFile string
+	// configErr holds the error from loading the config, if any
+	configErr error
 )
 
 func main() {
@@ -36,6 +38,7 @@ func init() {
 
 func initConfig() {
 	if err := config.Load(cfgFile); err != nil {
+		configErr = err
 		fmt.Fprintf(os.Stderr, "Warning: could not load config: %v\n", err)
 	}
 }
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/query"
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+	"github.com/spf13/cobra"
+)
+
+const metaTargetHelp = `The note is given by slug (or id or alias). Use "-" to read slugs from
+stdin, one per line, or select notes with --tag, --folder, and --query
+instead of a slug. --query takes a noti query, such as
+'FROM projects WHERE status = "active"' (see noti query --help).`
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Read and edit note frontmatter",
+	Long: `Read and edit frontmatter fields of one or more notes.
+
+Values are parsed as YAML, so "true" is a bool, "2024-03-01" a date, and
+"[a, b]" a list. Use --string to store a value as text.
+
+` + metaTargetHelp,
+}
+
+var metaGetCmd = &cobra.Command{
+	Use:   "get <slug> [key]",
+	Short: "Print a field, or all fields",
+	RunE:  runMetaGet,
+}
+
+var metaSetCmd = &cobra.Command{
+	Use:   "set <slug> <key> <value>",
+	Short: "Set a field",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMetaEdit(cmd, args, 2, false, func(fm *frontmatter.Frontmatter, key string, values []interface{}) error {
+			return fm.Set(key, values[0])
+		})
+	},
+}
+
+var metaUnsetCmd = &cobra.Command{
+	Use:   "unset <slug> <key>",
+	Short: "Remove a field",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMetaEdit(cmd, args, 1, false, func(fm *frontmatter.Frontmatter, key string, values []interface{}) error {
+			fm.Unset(key)
+			return nil
+		})
+	},
+}
+
+var metaAddCmd = &cobra.Command{
+	Use:   "add <slug> <key> <value>...",
+	Short: "Add values to a list field",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMetaEdit(cmd, args, 2, true, func(fm *frontmatter.Frontmatter, key string, values []interface{}) error {
+			return editList(fm, key, values, true)
+		})
+	},
+}
+
+var metaRemoveCmd = &cobra.Command{
+	Use:   "remove <slug> <key> <value>...",
+	Short: "Remove values from a list field",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMetaEdit(cmd, args, 2, true, func(fm *frontmatter.Frontmatter, key string, values []interface{}) error {
+			return editList(fm, key, values, false)
+		})
+	},
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add or remove tags on notes",
+	Long:  `Add or remove tags on notes. ` + metaTargetHelp,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <slug> <tag>...",
+	Short: "Add tags to notes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMetaEdit(cmd, withKey(args, "tags"), 2, true, func(fm *frontmatter.Frontmatter, key string, values []interface{}) error {
+			return editList(fm, key, values, true)
+		})
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <slug> <tag>...",
+	Short: "Remove tags from notes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMetaEdit(cmd, withKey(args, "tags"), 2, true, func(fm *frontmatter.Frontmatter, key string, values []interface{}) error {
+			return editList(fm, key, values, false)
+		})
+	},
+}
+
+var (
+	metaTag    string
+	metaFolder string
+	metaQuery  string
+	metaString bool
+)
+
+func init() {
+	rootCmd.AddCommand(metaCmd)
+	rootCmd.AddCommand(tagCmd)
+
+	metaCmd.AddCommand(metaGetCmd, metaSetCmd, metaUnsetCmd, metaAddCmd, metaRemoveCmd)
+	tagCmd.AddCommand(tagAddCmd, tagRemoveCmd)
+
+	metaCmd.PersistentFlags().StringVarP(&metaTag, "tag", "t", "", "edit all notes with this tag")
+	metaCmd.PersistentFlags().StringVarP(&metaFolder, "folder", "f", "", "edit all notes in this folder")
+	metaCmd.PersistentFlags().StringVarP(&metaQuery, "query", "q", "", "edit all notes matching this query")
+	metaCmd.PersistentFlags().BoolVar(&metaString, "string", false, "store values as text instead of parsing them")
+	tagCmd.PersistentFlags().StringVarP(&metaTag, "tag", "t", "", "edit all notes with this tag")
+	tagCmd.PersistentFlags().StringVarP(&metaFolder, "folder", "f", "", "edit all notes in this folder")
+	tagCmd.PersistentFlags().StringVarP(&metaQuery, "query", "q", "", "edit all notes matching this query")
+}
+
+// withKey inserts key after the target slug, so tag add/remove can share
+// the meta add/remove implementation
+func withKey(args []string, key string) []string {
+	if selectsByFilter() {
+		return append([]string{key}, args...)
+	}
+	if len(args) == 0 {
+		return args
+	}
+	return append([]string{args[0], key}, args[1:]...)
+}
+
+func selectsByFilter() bool {
+	return metaTag != "" || metaFolder != "" || metaQuery != ""
+}
+
+// metaTargets resolves the notes to work on and returns the remaining
+// arguments
+func metaTargets(args []string) ([]*notes.Document, []string, error) {
+	var slugs []string
+
+	if selectsByFilter() {
+		selected, err := notes.ListNotes(metaFolder, metaTag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not list notes: %w", err)
+		}
+		for _, note := range selected {
+			slugs = append(slugs, note.Slug)
+		}
+
+		if metaQuery != "" {
+			if slugs, err = queryTargets(slugs); err != nil {
+				return nil, nil, err
+			}
+		}
+	} else {
+		if len(args) == 0 {
+			return nil, nil, fmt.Errorf("a slug, \"-\", --tag, --folder, or --query is required")
+		}
+
+		if args[0] == "-" {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				// Take the first field so output of other commands can be piped in
+				if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+					slugs = append(slugs, fields[0])
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, nil, fmt.Errorf("could not read slugs: %w", err)
+			}
+		} else {
+			slugs = []string{args[0]}
+		}
+		args = args[1:]
+	}
+
+	root := config.Get().NotesDir
+	var docs []*notes.Document
+	for _, slug := range slugs {
+		note, err := notes.GetNote(slug)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not find note %q: %w", slug, err)
+		}
+
+		relPath, err := filepath.Rel(root, note.FilePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get relative path: %w", err)
+		}
+
+		doc, err := notes.LoadDocument(root, relPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if doc.ParseErr != nil {
+			return nil, nil, fmt.Errorf("%s: %w", slug, doc.ParseErr)
+		}
+		if doc.Frontmatter == nil {
+			doc.Frontmatter = &frontmatter.Frontmatter{}
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, args, nil
+}
+
+// queryTargets returns the slugs matching --query, in query order, that are
+// also in selected. The query runs over all notes so link counts are
+// complete.
+func queryTargets(selected []string) ([]string, error) {
+	q, err := query.Parse(metaQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	q.Select = []string{"slug"}
+
+	all, err := notes.ListNotes("", "")
+	if err != nil {
+		return nil, fmt.Errorf("could not list notes: %w", err)
+	}
+
+	inSelection := make(map[string]bool, len(selected))
+	for _, slug := range selected {
+		inSelection[slug] = true
+	}
+
+	var slugs []string
+	for _, row := range q.Run(all).Rows {
+		if slug, ok := row[0].(string); ok && inSelection[slug] {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs, nil
+}
+
+func runMetaGet(cmd *cobra.Command, args []string) error {
+	docs, rest, err := metaTargets(args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 1 {
+		return fmt.Errorf("expected at most one key, got %d", len(rest))
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	// slug -> key -> value
+	result := make(map[string]map[string]interface{})
+	for _, doc := range docs {
+		fields := make(map[string]interface{})
+		keys := rest
+		if len(keys) == 0 {
+			keys = doc.Frontmatter.Keys()
+		}
+		for _, key := range keys {
+			if value, ok := doc.Frontmatter.Get(key); ok {
+				fields[key] = value
+			}
+		}
+		result[doc.Slug()] = fields
+	}
+
+	if jsonOutput {
+		out := make(map[string]interface{}, len(result))
+		for slug, fields := range result {
+			out[slug] = fields
+		}
+		var selected interface{} = out
+		if len(docs) == 1 {
+			for _, fields := range result {
+				selected = fields
+				if len(rest) == 1 {
+					selected = fields[rest[0]]
+				}
+			}
+		}
+		// Dates print as they do in text and noti query output
+		data, err := json.MarshalIndent(query.JSONValue(selected), "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, doc := range docs {
+		slug := doc.Slug()
+		fields := result[slug]
+
+		keys := rest
+		if len(keys) == 0 {
+			keys = doc.Frontmatter.Keys()
+		}
+
+		for _, key := range keys {
+			value, ok := fields[key]
+			if !ok {
+				continue
+			}
+			switch {
+			case len(docs) == 1 && len(rest) == 1:
+				fmt.Println(frontmatter.FormatValue(value))
+			case len(docs) == 1:
+				fmt.Printf("%s: %s\n", key, frontmatter.FormatValue(value))
+			case len(rest) == 1:
+				fmt.Printf("%s: %s\n", slug, frontmatter.FormatValue(value))
+			default:
+				fmt.Printf("%s: %s: %s\n", slug, key, frontmatter.FormatValue(value))
+			}
+		}
+	}
+
+	return nil
+}
+
+// runMetaEdit applies change to the key and values in args for every
+// target note and writes the notes that changed
+func runMetaEdit(cmd *cobra.Command, args []string, minArgs int, variadic bool, change func(fm *frontmatter.Frontmatter, key string, values []interface{}) error) error {
+	docs, rest, err := metaTargets(args)
+	if err != nil {
+		return err
+	}
+
+	if len(rest) < minArgs || (!variadic && len(rest) > minArgs) {
+		return fmt.Errorf("wrong number of arguments for %s (see --help)", cmd.CommandPath())
+	}
+
+	key := rest[0]
+	raw := rest[1:]
+	// Tags being added go through the vocabulary; removals match as written
+	if key == "tags" && cmd.Name() != "remove" {
+		if raw, err = checkTags(raw); err != nil {
+			return err
+		}
+	}
+
+	values := make([]interface{}, 0, len(raw))
+	for _, s := range raw {
+		if metaString {
+			values = append(values, s)
+			continue
+		}
+		value, err := frontmatter.ParseValue(s)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+
+	root := config.Get().NotesDir
+	updated := 0
+	for _, doc := range docs {
+		if err := change(doc.Frontmatter, key, values); err != nil {
+			return fmt.Errorf("%s: %w", doc.Path, err)
+		}
+
+		data, err := doc.Render()
+		if err != nil {
+			return fmt.Errorf("could not format %s: %w", doc.Path, err)
+		}
+		if string(data) == string(doc.Data) {
+			continue
+		}
+
+		if err := notes.WriteAtomic(filepath.Join(root, filepath.FromSlash(doc.Path)), data); err != nil {
+			return fmt.Errorf("could not write %s: %w", doc.Path, err)
+		}
+		updated++
+	}
+
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+	if !quietOutput {
+		fmt.Printf("Updated %d of %d note(s)\n", updated, len(docs))
+	}
+
+	return nil
+}
+
+// editList adds values to, or removes them from, a list field
+func editList(fm *frontmatter.Frontmatter, key string, values []interface{}, add bool) error {
+	var current []interface{}
+	if existing, ok := fm.Get(key); ok {
+		switch v := existing.(type) {
+		case []string:
+			for _, item := range v {
+				current = append(current, item)
+			}
+		case []interface{}:
+			current = v
+		default:
+			current = []interface{}{v}
+		}
+	}
+
+	// Lists given as values, such as "[a, b]", add or remove each item
+	var items []interface{}
+	for _, value := range values {
+		if list, ok := value.([]interface{}); ok {
+			items = append(items, list...)
+		} else {
+			items = append(items, value)
+		}
+	}
+
+	contains := func(list []interface{}, item interface{}) bool {
+		for _, existing := range list {
+			if frontmatter.FormatValue(existing) == frontmatter.FormatValue(item) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var next []interface{}
+	if add {
+		next = current
+		for _, item := range items {
+			if !contains(next, item) {
+				next = append(next, item)
+			}
+		}
+	} else {
+		next = []interface{}{}
+		for _, item := range current {
+			if !contains(items, item) {
+				next = append(next, item)
+			}
+		}
+	}
+
+	if next == nil {
+		next = []interface{}{}
+	}
+	return fm.Set(key, next)
+}
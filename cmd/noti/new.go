@@ -12,20 +12,27 @@ import (
 var newCmd = &cobra.Command{
 	Use:   "new <title>",
 	Short: "Create a new note",
-	Long:  `Create a new note with the specified title`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runNew,
+	Long: `Create a new note with the specified title. An existing note is never
+overwritten: if the slug is taken, a numbered slug is used (or an error is
+returned when slug_on_collision is "error"). With --open-existing, the
+existing note is returned instead. With the date and zettel slug strategies,
+where every new slug is different, the existing note is the one in the same
+folder with the same title.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNew,
 }
 
 var (
-	newFolder string
-	newTags   []string
+	newFolder       string
+	newTags         []string
+	newOpenExisting bool
 )
 
 func init() {
 	rootCmd.AddCommand(newCmd)
 	newCmd.Flags().StringVarP(&newFolder, "folder", "f", "", "folder for the new note")
 	newCmd.Flags().StringSliceVarP(&newTags, "tags", "t", []string{}, "tags for the new note")
+	newCmd.Flags().BoolVar(&newOpenExisting, "open-existing", false, "return the existing note if one with the same slug (or title, for date and zettel slugs) exists")
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
@@ -40,7 +47,18 @@ func runNew(cmd *cobra.Command, args []string) error {
 		newTags = cfg.DefaultTags
 	}
 
-	note, err := notes.CreateNote(title, newFolder, newTags)
+	tags, err := checkTags(newTags)
+	if err != nil {
+		return err
+	}
+
+	var note *notes.Note
+	var existing bool
+	if newOpenExisting {
+		note, existing, err = notes.OpenOrCreateNote(title, newFolder, tags)
+	} else {
+		note, err = notes.CreateNote(title, newFolder, tags)
+	}
 	if err != nil {
 		return fmt.Errorf("could not create note: %w", err)
 	}
@@ -62,7 +80,11 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Printf("Created note: %s\n", note.Title)
+	if existing {
+		fmt.Printf("Opened existing note: %s\n", note.Title)
+	} else {
+		fmt.Printf("Created note: %s\n", note.Title)
+	}
 	fmt.Printf("  slug: %s\n", note.Slug)
 	fmt.Printf("  path: %s\n", note.FilePath)
 
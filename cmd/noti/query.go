@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/query"
+	"github.com/spf13/cobra"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query <query>",
+	Short: "Query notes by their frontmatter",
+	Long: `Query notes like a database, filtering and sorting on frontmatter fields.
+
+  noti query 'FROM folder:projects WHERE status = "active" SORT due ASC SELECT title, owner, due'
+
+Clauses may come in any order and are all optional:
+
+  FROM    folder:<name> (including subfolders) and tag:<name>, all must match
+  WHERE   comparisons with = != < <= > >= and contains, combined with AND,
+          OR, NOT, and parentheses; a bare field is true when it is set
+  SORT    fields, each followed by ASC (default) or DESC
+  SELECT  fields to output (default: slug, title)
+  LIMIT   maximum number of rows
+
+Besides any frontmatter key, these fields are available: slug, title,
+folder, id, tags, aliases, created, modified, words, links, backlinks.
+
+Quote string values; a bare word is a field name. Strings compare
+case-insensitively; dates are written as 2024-03-01. A
+list such as tags equals a value when any item does. Missing fields equal
+null and sort last.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runQuery,
+}
+
+var queryFormat string
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+	queryCmd.Flags().StringVar(&queryFormat, "format", query.FormatTable, "output format: table, csv, json, or markdown")
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	q, err := query.Parse(strings.Join(args, " "))
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	allNotes, err := notes.ListNotes("", "")
+	if err != nil {
+		return fmt.Errorf("could not list notes: %w", err)
+	}
+
+	result := q.Run(allNotes)
+
+	format := queryFormat
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		format = query.FormatJSON
+	}
+
+	return result.Render(os.Stdout, format)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var relatedCmd = &cobra.Command{
+	Use:   "related <slug>",
+	Short: "List notes similar to a note",
+	Long: `List the notes most similar to a note, comparing the words they use.
+
+Notes are compared by TF-IDF: words shared by the two notes count more the
+rarer they are in the vault. Words are stemmed with the first search
+language in .noti.yaml. Word counts are cached in the .noti directory and
+only recomputed for notes that changed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRelated,
+}
+
+var relatedLimit int
+
+func init() {
+	rootCmd.AddCommand(relatedCmd)
+	relatedCmd.Flags().IntVarP(&relatedLimit, "limit", "n", 10, "number of notes to list (0 for all)")
+}
+
+func runRelated(cmd *cobra.Command, args []string) error {
+	note, err := notes.GetNote(args[0])
+	if err != nil {
+		return fmt.Errorf("could not find note %q: %w", args[0], err)
+	}
+
+	allNotes, err := notes.ListNotes("", "")
+	if err != nil {
+		return fmt.Errorf("could not list notes: %w", err)
+	}
+
+	opts, err := searchOptions()
+	if err != nil {
+		return err
+	}
+
+	related, err := search.Related(note, allNotes, opts.Analyzers[0], relatedLimit)
+	if err != nil {
+		return fmt.Errorf("could not find related notes: %w", err)
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(related, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if quietOutput {
+		for _, r := range related {
+			fmt.Println(r.Note.Slug)
+		}
+		return nil
+	}
+
+	if len(related) == 0 {
+		fmt.Println("No related notes found")
+		return nil
+	}
+
+	fmt.Printf("Notes related to %s:\n\n", note.Title)
+	for _, r := range related {
+		fmt.Printf("  %.3f  %s\n", r.Score, r.Note.Title)
+		fmt.Printf("         slug: %s\n", r.Note.Slug)
+		fmt.Printf("         shared: %s\n", strings.Join(r.SharedTerms, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "Manage saved searches",
+	Long: `Manage named searches stored in .noti.yaml in the notes directory, so
+they are shared with everyone using the notes. Queries use the same syntax
+as noti search, including tag: and folder: qualifiers.`,
+}
+
+var savedAddCmd = &cobra.Command{
+	Use:     "add <name> <query>",
+	Short:   "Save a search",
+	Example: `  noti saved add inbox 'tag:inbox -tag:done'`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    runSavedAdd,
+}
+
+var savedRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSavedRun,
+}
+
+var savedListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	Args:  cobra.NoArgs,
+	RunE:  runSavedList,
+}
+
+var savedRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSavedRemove,
+}
+
+var savedForce bool
+
+var savedNamePattern = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// savedSearch is a named search query
+type savedSearch struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+func init() {
+	rootCmd.AddCommand(savedCmd)
+	savedCmd.AddCommand(savedAddCmd, savedRunCmd, savedListCmd, savedRemoveCmd)
+	savedAddCmd.Flags().BoolVar(&savedForce, "force", false, "replace an existing saved search")
+	addSearchFlags(savedRunCmd)
+}
+
+func runSavedAdd(cmd *cobra.Command, args []string) error {
+	name, query := args[0], strings.TrimSpace(args[1])
+	if !savedNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid name %q: use letters, digits, - and _", name)
+	}
+	if query == "" {
+		return fmt.Errorf("query is empty")
+	}
+
+	vault, err := config.LoadVault()
+	if err != nil {
+		return err
+	}
+	if _, ok := vault.Searches[name]; ok && !savedForce {
+		return fmt.Errorf("saved search %q already exists (use --force to replace it)", name)
+	}
+
+	if vault.Searches == nil {
+		vault.Searches = make(map[string]string)
+	}
+	vault.Searches[name] = query
+
+	if err := config.SaveSearches(vault.Searches); err != nil {
+		return err
+	}
+
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+	if !quietOutput {
+		fmt.Printf("Saved search %q: %s\n", name, query)
+	}
+	return nil
+}
+
+func runSavedRun(cmd *cobra.Command, args []string) error {
+	query, err := savedQuery(args[0])
+	if err != nil {
+		return err
+	}
+
+	opts, err := searchOptions()
+	if err != nil {
+		return err
+	}
+
+	results, err := search.Search(query, opts)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	return printSearchResults(cmd, query, results)
+}
+
+func runSavedList(cmd *cobra.Command, args []string) error {
+	vault, err := config.LoadVault()
+	if err != nil {
+		return err
+	}
+
+	saved := make([]savedSearch, 0, len(vault.Searches))
+	for name, query := range vault.Searches {
+		saved = append(saved, savedSearch{Name: name, Query: query})
+	}
+	sort.Slice(saved, func(i, j int) bool {
+		return saved[i].Name < saved[j].Name
+	})
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(saved, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if quietOutput {
+		for _, s := range saved {
+			fmt.Println(s.Name)
+		}
+		return nil
+	}
+
+	if len(saved) == 0 {
+		fmt.Println("No saved searches")
+		return nil
+	}
+
+	for _, s := range saved {
+		fmt.Printf("%-20s %s\n", s.Name, s.Query)
+	}
+	return nil
+}
+
+func runSavedRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	vault, err := config.LoadVault()
+	if err != nil {
+		return err
+	}
+	if _, ok := vault.Searches[name]; !ok {
+		return fmt.Errorf("no saved search named %q", name)
+	}
+
+	delete(vault.Searches, name)
+	if err := config.SaveSearches(vault.Searches); err != nil {
+		return err
+	}
+
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+	if !quietOutput {
+		fmt.Printf("Removed saved search %q\n", name)
+	}
+	return nil
+}
+
+// savedQuery returns the query of a saved search
+func savedQuery(name string) (string, error) {
+	vault, err := config.LoadVault()
+	if err != nil {
+		return "", err
+	}
+
+	query, ok := vault.Searches[name]
+	if !ok {
+		return "", fmt.Errorf("no saved search named %q", name)
+	}
+	return query, nil
+}
@@ -3,7 +3,10 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/devjasha/noti-vim/internal/config"
 	"github.com/devjasha/noti-vim/internal/search"
 	"github.com/spf13/cobra"
 )
@@ -11,26 +14,106 @@ import (
 var searchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search notes by content",
-	Long:  `Search for notes containing the specified query in title, content, or tags`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runSearch,
+	Long: `Search for notes containing the specified query in title, content, or tags.
+
+Qualifiers narrow the notes searched; the remaining words are searched for
+as a phrase, and a query of only qualifiers lists the matching notes:
+
+  tag:inbox     notes with the tag (or a nested tag below it)
+  folder:work   notes in the folder or its subfolders
+  -tag:done     notes without the tag
+  -folder:old   notes outside the folder
+  -draft        notes not containing the word
+  section:Todo  lines under a heading, or a path such as "API > Errors"
+  -section:Log  lines outside a heading
+  in:code       lines of fenced code blocks (in:prose for the rest)
+  lang:go       lines of code blocks in a language (-lang:go to skip them)
+
+  noti search 'tag:inbox -tag:done'
+  noti search 'folder:projects deadline'
+  noti search 'section:"Open questions" api'
+
+By default the text matches case-insensitively anywhere in a line, and
+also word by word after stemming, so "running" finds "run" and "cafe"
+finds "Café". Stemming languages are set in .noti.yaml:
+
+  search:
+    languages: [english, german]
+
+Use --regex for RE2 regular expressions, --fuzzy to tolerate typos, and
+--case-sensitive or --whole-word to match exactly, without stemming.
+Excluded -words match by the same rules. JSON output includes the column
+and length of each match.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
 }
 
+var (
+	searchOpts  search.Options
+	searchColor string
+)
+
 func init() {
 	rootCmd.AddCommand(searchCmd)
+	addSearchFlags(searchCmd)
+}
+
+// searchOptions returns the matching options from the flags, with the
+// vault's search languages
+func searchOptions() (search.Options, error) {
+	opts := searchOpts
+
+	vault, err := config.LoadVault()
+	if err != nil {
+		return opts, err
+	}
+
+	stopWords := vault.Search.StopWords == nil || *vault.Search.StopWords
+	opts.Analyzers, err = search.NewAnalyzers(vault.Search.Languages, stopWords)
+	if err != nil {
+		return opts, fmt.Errorf("invalid search configuration in %s: %w", config.VaultFile, err)
+	}
+
+	return opts, nil
+}
+
+// addSearchFlags adds the flags that control matching
+func addSearchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&searchOpts.Regex, "regex", "r", false, "treat the query text as a regular expression")
+	cmd.Flags().BoolVar(&searchOpts.Fuzzy, "fuzzy", false, "match words with small typos")
+	cmd.Flags().BoolVarP(&searchOpts.CaseSensitive, "case-sensitive", "s", false, "match case exactly")
+	cmd.Flags().BoolVarP(&searchOpts.WholeWord, "whole-word", "w", false, "only match whole words")
+	cmd.Flags().IntVar(&searchOpts.SnippetWindow, "window", search.DefaultSnippetWindow, "characters of context shown around a match")
+	cmd.Flags().IntVar(&searchOpts.MaxMatches, "max-matches", 5, "matches shown per note (0 for all)")
+	cmd.Flags().StringVar(&searchColor, "color", "auto", "highlight matches: auto, always, or never")
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
 	query := args[0]
 
-	results, err := search.Search(query)
+	opts, err := searchOptions()
+	if err != nil {
+		return err
+	}
+
+	results, err := search.Search(query, opts)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
 
+	return printSearchResults(cmd, query, results)
+}
+
+// printSearchResults prints search results as JSON, slugs, or text
+func printSearchResults(cmd *cobra.Command, query string, results []*search.SearchResult) error {
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	quietOutput, _ := cmd.Flags().GetBool("quiet")
 
+	color, err := useColor(searchColor)
+	if err != nil {
+		return err
+	}
+
 	if jsonOutput {
 		data, err := json.MarshalIndent(results, "", "  ")
 		if err != nil {
@@ -61,19 +144,82 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		if len(result.Note.Tags) > 0 {
 			fmt.Printf("   tags: %v\n", result.Note.Tags)
 		}
-		fmt.Printf("   %d match(es):\n", len(result.Matches))
+		if len(result.Matches) == 0 {
+			fmt.Println()
+			continue
+		}
+		fmt.Printf("   %d match(es):\n", len(result.Matches)+result.More)
 
 		for _, match := range result.Matches {
-			if match.Context == "title" {
-				fmt.Printf("     • in title: %s\n", match.Line)
-			} else if match.Context == "tag" {
-				fmt.Printf("     • in tag: %s\n", match.Line)
-			} else {
-				fmt.Printf("     • line %d: %s\n", match.LineNumber, match.Line)
+			snippet := highlight(match.Snippet, match.Highlights, color)
+			switch match.Context {
+			case search.ContextTitle:
+				fmt.Printf("     • in title: %s\n", snippet)
+			case search.ContextTag:
+				fmt.Printf("     • in tag: %s\n", snippet)
+			default:
+				where := fmt.Sprintf("line %d", match.LineNumber)
+				if match.Section != "" {
+					where += " (" + match.Section + ")"
+				}
+				if match.Code {
+					where += " [" + codeLabel(match.Lang) + "]"
+				}
+				fmt.Printf("     • %s: %s\n", where, snippet)
 			}
 		}
+		if result.More > 0 {
+			fmt.Printf("     +%d more\n", result.More)
+		}
 		fmt.Println()
 	}
 
 	return nil
 }
+
+// codeLabel names a code block by its language
+func codeLabel(lang string) string {
+	if lang == "" {
+		return "code"
+	}
+	return lang
+}
+
+// useColor decides from a --color value whether to highlight with ANSI
+// escapes. "auto" highlights when stdout is a terminal and NO_COLOR is not
+// set.
+func useColor(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		info, err := os.Stdout.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("invalid --color %q (use auto, always, or never)", mode)
+	}
+}
+
+// highlight marks the ranges of s in bold red
+func highlight(s string, ranges []search.Range, color bool) string {
+	if !color || len(ranges) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, r := range ranges {
+		b.WriteString(s[pos:r.Start])
+		b.WriteString("\033[1;31m")
+		b.WriteString(s[r.Start:r.End])
+		b.WriteString("\033[0m")
+		pos = r.End
+	}
+	b.WriteString(s[pos:])
+	return b.String()
+}
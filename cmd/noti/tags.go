@@ -1,26 +1,121 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/diff"
+	"github.com/devjasha/noti-vim/internal/git"
 	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/tags"
+	"github.com/devjasha/noti-vim/internal/vocab"
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
 	"github.com/spf13/cobra"
 )
 
 var tagsCmd = &cobra.Command{
 	Use:   "tags",
 	Short: "List all tags",
-	Long:  `List all tags used across notes with usage counts`,
-	RunE:  runTags,
+	Long: `List all tags used across notes with usage counts. Inline #hashtags in
+note content count as tags; tags that appear only inline, or both inline
+and in frontmatter, are marked [inline] or [both].
+
+Tags can be nested with "/", as in project/alpha/backend. With --tree,
+nested tags are shown as a tree and each parent counts the notes tagged
+with it or anything below it.`,
+	RunE: runTags,
+}
+
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag in every note",
+	Long: `Rename a tag in the frontmatter and inline #hashtags of every note.
+Tags nested below it are renamed too: renaming project to work turns
+project/alpha into work/alpha.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := tags.CheckMerge(args[:1], args[1]); err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Rename tag %s to %s", args[0], args[1])
+		return runTagsEdit(cmd, tags.Retag(tags.Rename(args[0], args[1])), message, nil)
+	},
+}
+
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge <tag>... --into <tag>",
+	Short: "Merge tags into one",
+	Long: `Replace each of the tags with the --into tag in every note. Tags nested
+below them move along: merging project into work turns project/alpha into
+work/alpha.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if tagsInto == "" {
+			return fmt.Errorf("--into is required")
+		}
+		if err := tags.CheckMerge(args, tagsInto); err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Merge tags %s into %s", strings.Join(args, ", "), tagsInto)
+		return runTagsEdit(cmd, tags.Retag(tags.Merge(args, tagsInto)), message, nil)
+	},
+}
+
+var tagsRemoveCmd = &cobra.Command{
+	Use:   "remove <tag>",
+	Short: "Remove a tag from every note",
+	Long: `Remove a tag from the frontmatter and inline #hashtags of every note.
+Tags nested below it, such as tag/sub, are kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		message := fmt.Sprintf("Remove tag %s", args[0])
+		return runTagsEdit(cmd, tags.Retag(tags.Remove(args[0])), message, nil)
+	},
 }
 
-var showCounts bool
+var tagsPromoteCmd = &cobra.Command{
+	Use:   "promote [slugs...]",
+	Short: "Move inline #hashtags into frontmatter tags",
+	Long: `Add the inline #hashtags of every note (or the given notes) to its
+frontmatter tags. With --strip, the hashtags are removed from the content.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTagsEdit(cmd, tags.Promote(tagsStrip), "Promote inline tags to frontmatter", args)
+	},
+}
+
+var (
+	showCounts   bool
+	tagsTree     bool
+	tagsInto     string
+	tagsDryRun   bool
+	tagsYes      bool
+	tagsNoCommit bool
+	tagsStrip    bool
+)
 
 func init() {
 	rootCmd.AddCommand(tagsCmd)
 	tagsCmd.Flags().BoolVarP(&showCounts, "count", "c", true, "show usage counts")
+	tagsCmd.Flags().BoolVar(&tagsTree, "tree", false, "show nested tags as a tree with counts rolled up to parents")
+
+	tagsCmd.AddCommand(tagsRenameCmd)
+	tagsCmd.AddCommand(tagsMergeCmd)
+	tagsCmd.AddCommand(tagsRemoveCmd)
+	tagsCmd.AddCommand(tagsPromoteCmd)
+
+	tagsMergeCmd.Flags().StringVar(&tagsInto, "into", "", "tag to merge into")
+	tagsPromoteCmd.Flags().BoolVar(&tagsStrip, "strip", false, "remove the hashtags from the note content")
+	for _, c := range []*cobra.Command{tagsRenameCmd, tagsMergeCmd, tagsRemoveCmd, tagsPromoteCmd} {
+		c.Flags().BoolVarP(&tagsDryRun, "dry-run", "n", false, "show the changes without writing them")
+		c.Flags().BoolVarP(&tagsYes, "yes", "y", false, "apply without asking for confirmation")
+		c.Flags().BoolVar(&tagsNoCommit, "no-commit", false, "do not commit the changes to git")
+	}
 }
 
 func runTags(cmd *cobra.Command, args []string) error {
@@ -29,23 +124,57 @@ func runTags(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not list notes: %w", err)
 	}
 
-	// Count tag occurrences
+	vocabulary, err := vocab.Load()
+	if err != nil {
+		return err
+	}
+
+	if tagsTree {
+		return printTagTree(cmd, allNotes, vocabulary)
+	}
+
+	// Count the notes using each tag, and where they declare it
 	tagCounts := make(map[string]int)
+	fromFrontmatter := make(map[string]bool)
+	fromInline := make(map[string]bool)
 	for _, note := range allNotes {
-		for _, tag := range note.Tags {
+		for _, tag := range note.AllTags() {
 			tagCounts[tag]++
+			switch note.TagSource(tag) {
+			case notes.TagSourceFrontmatter:
+				fromFrontmatter[tag] = true
+			case notes.TagSourceInline:
+				fromInline[tag] = true
+			case notes.TagSourceBoth:
+				fromFrontmatter[tag] = true
+				fromInline[tag] = true
+			}
 		}
 	}
 
 	// Convert to sorted slice
 	type tagInfo struct {
-		Tag   string `json:"tag"`
-		Count int    `json:"count"`
+		Tag         string `json:"tag"`
+		Count       int    `json:"count"`
+		Source      string `json:"source"`
+		Description string `json:"description,omitempty"`
 	}
 
 	var tags []tagInfo
 	for tag, count := range tagCounts {
-		tags = append(tags, tagInfo{Tag: tag, Count: count})
+		source := notes.TagSourceFrontmatter
+		if fromInline[tag] {
+			source = notes.TagSourceInline
+			if fromFrontmatter[tag] {
+				source = notes.TagSourceBoth
+			}
+		}
+		tags = append(tags, tagInfo{
+			Tag:         tag,
+			Count:       count,
+			Source:      source,
+			Description: vocabulary.Description(tag),
+		})
 	}
 
 	// Sort by count (descending), then by name
@@ -83,16 +212,301 @@ func runTags(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Found %d tag(s):\n\n", len(tags))
 	for _, tag := range tags {
+		source := ""
+		if tag.Source != notes.TagSourceFrontmatter {
+			source = fmt.Sprintf(" [%s]", tag.Source)
+		}
+		if tag.Description != "" {
+			source += "  " + tag.Description
+		}
 		if showCounts {
-			fmt.Printf("  %-20s (%d note%s)\n", tag.Tag, tag.Count, plural(tag.Count))
+			fmt.Printf("  %-20s (%d note%s)%s\n", tag.Tag, tag.Count, plural(tag.Count), source)
 		} else {
-			fmt.Printf("  %s\n", tag.Tag)
+			fmt.Printf("  %s%s\n", tag.Tag, source)
+		}
+	}
+
+	return nil
+}
+
+type tagNode struct {
+	Tag         string `json:"tag"`
+	Count       int    `json:"count"`
+	Description string `json:"description,omitempty"`
+}
+
+// printTagTree prints nested tags with counts rolled up to their parents.
+// A note is counted once per node even if it has several tags below it.
+func printTagTree(cmd *cobra.Command, allNotes []*notes.Note, vocabulary *vocab.Vocabulary) error {
+	counts := make(map[string]int)
+	for _, note := range allNotes {
+		seen := make(map[string]bool)
+		for _, tag := range note.AllTags() {
+			for _, node := range notes.TagAncestors(tag) {
+				if node != "" && !seen[node] {
+					seen[node] = true
+					counts[node]++
+				}
+			}
+		}
+	}
+
+	nodes := make([]tagNode, 0, len(counts))
+	for tag, count := range counts {
+		nodes = append(nodes, tagNode{Tag: tag, Count: count, Description: vocabulary.Description(tag)})
+	}
+
+	// Compare segment by segment so children follow their parent
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := strings.Split(nodes[i].Tag, "/"), strings.Split(nodes[j].Tag, "/")
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(nodes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if quietOutput {
+		for _, node := range nodes {
+			fmt.Println(node.Tag)
+		}
+		return nil
+	}
+
+	if len(nodes) == 0 {
+		fmt.Println("No tags found")
+		return nil
+	}
+
+	for _, node := range nodes {
+		depth := strings.Count(node.Tag, "/")
+		name := node.Tag[strings.LastIndex(node.Tag, "/")+1:]
+		description := ""
+		if node.Description != "" {
+			description = "  " + node.Description
+		}
+		fmt.Printf("%s# %s (%d)%s\n", strings.Repeat("  ", depth), name, node.Count, description)
+	}
+
+	return nil
+}
+
+// runTagsEdit shows the diff of a tag edit of all notes (or the given
+// slugs), asks for confirmation, writes the notes, and commits them together
+func runTagsEdit(cmd *cobra.Command, edit tags.Edit, message string, slugs []string) error {
+	root := config.Get().NotesDir
+
+	var docs []*notes.Document
+	var err error
+	if len(slugs) == 0 {
+		docs, err = notes.LoadDocuments(root)
+	} else {
+		for _, slug := range slugs {
+			doc, loadErr := notes.LoadDocument(root, slug+".md")
+			if loadErr != nil {
+				return loadErr
+			}
+			docs = append(docs, doc)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	vocabulary, err := vocab.Load()
+	if err != nil {
+		return err
+	}
+
+	var unknown []string
+	changes, err := tags.PlanEdit(docs, vocabularyEdit(vocabulary, edit, &unknown))
+	if err != nil {
+		return err
+	}
+
+	if len(unknown) > 0 {
+		if _, _, err := vocabulary.Check(unknown); err != nil {
+			return err
+		}
+		warnUnknownTags(unknown)
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+
+	if len(changes) == 0 {
+		if jsonOutput {
+			fmt.Println("[]")
+		} else if !quietOutput {
+			fmt.Println("No notes to change")
+		}
+		return nil
+	}
+
+	if jsonOutput {
+		type changed struct {
+			Slug string   `json:"slug"`
+			Tags []string `json:"tags"`
+		}
+		var out []changed
+		for _, c := range changes {
+			out = append(out, changed{Slug: c.Slug(), Tags: c.Tags})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if !quietOutput {
+		for _, c := range changes {
+			fmt.Print(diff.Unified("a/"+c.Path, "b/"+c.Path, string(c.Before), string(c.After), 3))
+		}
+		fmt.Println()
+	}
+
+	if tagsDryRun {
+		if !jsonOutput {
+			fmt.Printf("Would change %d note(s)\n", len(changes))
+		}
+		return nil
+	}
+
+	if !tagsYes && !confirm(fmt.Sprintf("%s in %d note(s)?", message, len(changes))) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	paths := make([]string, 0, len(changes))
+	for _, c := range changes {
+		if err := notes.WriteAtomic(filepath.Join(root, filepath.FromSlash(c.Path)), c.After); err != nil {
+			return fmt.Errorf("could not write %s: %w", c.Path, err)
+		}
+		paths = append(paths, c.Path)
+	}
+
+	if !tagsNoCommit && git.IsGitRepo() {
+		if err := git.Commit(message, paths...); err != nil {
+			return fmt.Errorf("notes updated but commit failed: %w", err)
 		}
 	}
 
+	if !jsonOutput && !quietOutput {
+		fmt.Printf("Updated %d note(s)\n", len(changes))
+	}
+
 	return nil
 }
 
+// vocabularyEdit wraps edit so the tags it adds to a note, in frontmatter
+// or as inline #hashtags, are normalized against the vocabulary; the note's
+// other tags are left as they are. Added tags that are not in the
+// vocabulary are appended to unknown.
+func vocabularyEdit(v *vocab.Vocabulary, edit tags.Edit, unknown *[]string) tags.Edit {
+	canonical := func(tag string) string {
+		c, ok := v.Canonical(tag)
+		if !ok {
+			*unknown = append(*unknown, tag)
+		}
+		return c
+	}
+
+	return func(fm *frontmatter.Frontmatter, content string) (string, bool) {
+		before := make(map[string]bool)
+		for _, tag := range fm.Tags {
+			before[tag] = true
+		}
+		beforeInline := make(map[string]bool)
+		for _, tag := range notes.InlineTags(content) {
+			beforeInline[tag] = true
+		}
+
+		content, changed := edit(fm, content)
+		if !changed {
+			return content, false
+		}
+
+		seen := make(map[string]bool)
+		updated := make([]string, 0, len(fm.Tags))
+		for _, tag := range fm.Tags {
+			if !before[tag] {
+				tag = canonical(tag)
+			}
+			if !seen[tag] {
+				seen[tag] = true
+				updated = append(updated, tag)
+			}
+		}
+		fm.Tags = updated
+
+		rewrite := make(map[string]string)
+		for _, tag := range notes.InlineTags(content) {
+			if !beforeInline[tag] {
+				if c := canonical(tag); c != tag {
+					rewrite[tag] = c
+				}
+			}
+		}
+		if len(rewrite) > 0 {
+			content = notes.RewriteInlineTags(content, rewrite)
+		}
+
+		return content, true
+	}
+}
+
+// checkTags normalizes tags against the vault's tag vocabulary, warning
+// about (or, in strict mode, rejecting) tags it does not know
+func checkTags(tagList []string) ([]string, error) {
+	vocabulary, err := vocab.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, unknown, err := vocabulary.Check(tagList)
+	if err != nil {
+		return nil, err
+	}
+	warnUnknownTags(unknown)
+
+	return normalized, nil
+}
+
+func warnUnknownTags(unknown []string) {
+	seen := make(map[string]bool)
+	for _, tag := range unknown {
+		if !seen[tag] {
+			seen[tag] = true
+			fmt.Fprintf(os.Stderr, "Warning: tag %q is not in %s\n", tag, vocab.Source)
+		}
+	}
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 func plural(count int) string {
 	if count == 1 {
 		return ""
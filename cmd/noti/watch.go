@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch notes and sync automatically",
+	Long: `Watch the notes directory for changes, commit after each quiet period,
+and pull/push on an interval. Changes that keep coming without a quiet
+period are committed --max-delay after the first one. Only one watcher runs per notes directory.
+Activity is logged to .noti/watch.log in the notes directory.`,
+	RunE: runWatch,
+}
+
+var (
+	watchDebounce     time.Duration
+	watchMaxDelay     time.Duration
+	watchInterval     time.Duration
+	watchPoll         bool
+	watchPollInterval time.Duration
+	watchDetach       bool
+	watchStop         bool
+)
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().DurationVarP(&watchDebounce, "debounce", "d", 5*time.Second, "quiet period before committing changes")
+	watchCmd.Flags().DurationVar(&watchMaxDelay, "max-delay", time.Minute, "commit changes this long after the first one even without a quiet period (0 waits for one)")
+	watchCmd.Flags().DurationVarP(&watchInterval, "interval", "i", 5*time.Minute, "pull/push interval (0 disables syncing)")
+	watchCmd.Flags().BoolVar(&watchPoll, "poll", false, "use polling instead of native file events")
+	watchCmd.Flags().DurationVar(&watchPollInterval, "poll-interval", 2*time.Second, "scan interval when polling")
+	watchCmd.Flags().BoolVar(&watchDetach, "detach", false, "run in the background")
+	watchCmd.Flags().BoolVar(&watchStop, "stop", false, "stop a running watcher")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return err
+	}
+
+	lockPath := filepath.Join(stateDir, "watch.pid")
+	logPath := filepath.Join(stateDir, "watch.log")
+
+	if watchStop {
+		pid, err := watch.Stop(lockPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Stopped noti watch (pid %d)\n", pid)
+		return nil
+	}
+
+	if watchDetach {
+		return detachWatch(lockPath, logPath)
+	}
+
+	release, err := watch.AcquireLock(lockPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	defer logFile.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	quietOutput, _ := cmd.Flags().GetBool("quiet")
+	if !quietOutput {
+		fmt.Printf("Watching %s (log: %s)\n", config.Get().NotesDir, logPath)
+		fmt.Println("Press Ctrl+C to stop")
+	}
+
+	return watch.Run(ctx, watch.Options{
+		Root:         config.Get().NotesDir,
+		Debounce:     watchDebounce,
+		MaxDelay:     watchMaxDelay,
+		SyncInterval: watchInterval,
+		Poll:         watchPoll,
+		PollInterval: watchPollInterval,
+		Logger:       log.New(logFile, "", log.LstdFlags),
+	})
+}
+
+// detachWatch starts a new watcher process in the background
+func detachWatch(lockPath, logPath string) error {
+	if pid, ok := watch.Running(lockPath); ok {
+		return fmt.Errorf("noti watch is already running (pid %d)", pid)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("could not find noti executable: %w", err)
+	}
+
+	args := []string{"watch", "--quiet",
+		"--debounce", watchDebounce.String(),
+		"--max-delay", watchMaxDelay.String(),
+		"--interval", watchInterval.String(),
+		"--poll-interval", watchPollInterval.String(),
+	}
+	if watchPoll {
+		args = append(args, "--poll")
+	}
+	if cfgFile != "" {
+		args = append(args, "--config", cfgFile)
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	defer logFile.Close()
+
+	child := exec.Command(exe, args...)
+	child.Stdout = logFile
+	child.Stderr = logFile
+	child.SysProcAttr = watch.DetachAttr()
+
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("could not start watcher: %w", err)
+	}
+
+	fmt.Printf("Started noti watch in the background (pid %d)\n", child.Process.Pid)
+	fmt.Printf("  log: %s\n", logPath)
+	return child.Process.Release()
+}
@@ -15,10 +15,22 @@ type Config struct {
 	Editor        string   `yaml:"editor"`
 	GitAutoCommit bool     `yaml:"git_auto_commit"`
 	GitAutoPush   bool     `yaml:"git_auto_push"`
+	GitBackend    string   `yaml:"git_backend"`
+	// SlugStrategy is "title", "date" or "zettel"
+	SlugStrategy  string `yaml:"slug_strategy,omitempty"`
+	SlugMaxLength int    `yaml:"slug_max_length,omitempty"`
+	// SlugOnCollision is "suffix" (append -2, -3, ...) or "error"
+	SlugOnCollision string `yaml:"slug_on_collision,omitempty"`
+	// IDFormat is "timestamp" or "ulid" to give new notes an id; empty for none
+	IDFormat string `yaml:"id_format,omitempty"`
 }
 
 var current *Config
 
+// currentFile is the file the configuration is loaded from, set even if
+// loading it failed
+var currentFile string
+
 // Load loads the configuration from the specified file or default location
 func Load(cfgFile string) error {
 	if cfgFile == "" {
@@ -28,6 +40,7 @@ func Load(cfgFile string) error {
 		}
 		cfgFile = filepath.Join(homeDir, ".config", "noti", "config.yaml")
 	}
+	currentFile = cfgFile
 
 	// Create default config if file doesn't exist
 	if _, err := os.Stat(cfgFile); os.IsNotExist(err) {
@@ -70,23 +83,43 @@ func Get() *Config {
 			Editor:        "vim",
 			GitAutoCommit: false,
 			GitAutoPush:   false,
+			GitBackend:    "exec",
 		}
 	}
 	return current
 }
 
-// Save saves the current configuration
+// StateDir returns the vault's .noti directory, creating it if needed.
+// The directory holds local state (locks, logs, caches) and is ignored by git.
+func StateDir() (string, error) {
+	dir := filepath.Join(Get().NotesDir, ".noti")
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("could not create state directory: %w", err)
+	}
+
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		if err := os.WriteFile(ignore, []byte("*\n"), 0644); err != nil {
+			return "", fmt.Errorf("could not write state .gitignore: %w", err)
+		}
+	}
+
+	return dir, nil
+}
+
+// File returns the path of the configuration file, whether or not it loaded
+func File() string {
+	return currentFile
+}
+
+// Save writes the current configuration back to the file it was loaded from
 func Save() error {
 	if current == nil {
 		return fmt.Errorf("no configuration loaded")
 	}
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("could not get home directory: %w", err)
-	}
-
-	cfgFile := filepath.Join(homeDir, ".config", "noti", "config.yaml")
+	cfgFile := currentFile
 	cfgDir := filepath.Dir(cfgFile)
 
 	if err := os.MkdirAll(cfgDir, 0755); err != nil {
@@ -124,6 +157,7 @@ func createDefaultConfig(cfgFile string) error {
 		Editor:        os.Getenv("EDITOR"),
 		GitAutoCommit: false,
 		GitAutoPush:   false,
+		GitBackend:    "exec",
 	}
 
 	if defaultCfg.Editor == "" {
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VaultFile is the name of the per-vault configuration file in the notes
+// directory. Unlike the user config it is meant to be committed and shared.
+const VaultFile = ".noti.yaml"
+
+// Vault is the configuration shared by everyone using a notes directory
+type Vault struct {
+	Lint LintConfig `yaml:"lint,omitempty"`
+	// Searches maps saved search names to search queries
+	Searches map[string]string `yaml:"searches,omitempty"`
+	Search   SearchConfig      `yaml:"search,omitempty"`
+	// Vocabulary lists the tags the vault agrees to use
+	Vocabulary VocabularyConfig `yaml:"vocabulary,omitempty"`
+}
+
+// VocabularyConfig is the tag vocabulary of a vault
+type VocabularyConfig struct {
+	// Strict rejects tags that are not in the vocabulary instead of warning
+	Strict bool                `yaml:"strict,omitempty"`
+	Tags   map[string]TagEntry `yaml:"tags,omitempty"`
+}
+
+// TagEntry describes a canonical tag
+type TagEntry struct {
+	Description string   `yaml:"description,omitempty"`
+	Synonyms    []string `yaml:"synonyms,omitempty"`
+}
+
+// SearchConfig configures noti search
+type SearchConfig struct {
+	// Languages choose stemming and stop words: "english" (the default),
+	// "german", or "none" to only fold case and accents
+	Languages []string `yaml:"languages,omitempty"`
+	// StopWords can be set to false to keep words such as "the" or "und"
+	StopWords *bool `yaml:"stop_words,omitempty"`
+}
+
+// LintConfig configures noti lint
+type LintConfig struct {
+	// Rules enables or disables rules by name; unlisted rules use their default
+	Rules map[string]bool `yaml:"rules,omitempty"`
+}
+
+// VaultPath returns the path of the vault configuration file
+func VaultPath() string {
+	return filepath.Join(Get().NotesDir, VaultFile)
+}
+
+// LoadVault reads the vault configuration, returning an empty configuration
+// if the notes directory has none
+func LoadVault() (*Vault, error) {
+	var vault Vault
+
+	data, err := os.ReadFile(VaultPath())
+	if os.IsNotExist(err) {
+		return &vault, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", VaultFile, err)
+	}
+
+	if err := yaml.Unmarshal(data, &vault); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", VaultFile, err)
+	}
+
+	return &vault, nil
+}
+
+// SaveSearches replaces the saved searches in the vault configuration.
+// Only the searches section of the file is rewritten, so the rest keeps its
+// comments, order and any keys noti does not know.
+func SaveSearches(searches map[string]string) error {
+	path := VaultPath()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read %s: %w", VaultFile, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("could not parse %s: %w", VaultFile, err)
+	}
+
+	var root *yaml.Node
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		root = doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return fmt.Errorf("could not update %s: it is not a mapping", VaultFile)
+		}
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	// The searches section runs from its key to the next top-level key,
+	// leaving comments and blank lines before that key in place
+	start, end := len(lines), len(lines)
+	key, value := &yaml.Node{Kind: yaml.ScalarNode, Value: "searches"}, &yaml.Node{Kind: yaml.MappingNode}
+	if root != nil {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value != "searches" {
+				continue
+			}
+			key, value = root.Content[i], root.Content[i+1]
+			start = key.Line - 1
+			if i+2 < len(root.Content) {
+				end = root.Content[i+2].Line - 1
+			}
+			for end > start+1 && isBlankOrComment(lines[end-1]) {
+				end--
+			}
+			break
+		}
+	}
+
+	block, err := searchesBlock(key, value, searches, indentOf(root))
+	if err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	for _, line := range lines[:start] {
+		buf.WriteString(line)
+	}
+	if start == len(lines) && start > 0 && !strings.HasSuffix(lines[start-1], "\n") {
+		buf.WriteString("\n")
+	}
+	buf.WriteString(block)
+	for _, line := range lines[end:] {
+		buf.WriteString(line)
+	}
+
+	if err := os.WriteFile(path, []byte(buf.String()), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", VaultFile, err)
+	}
+
+	return nil
+}
+
+// searchesBlock renders the searches section after updating the existing
+// mapping node in place, so unchanged entries keep their comments
+func searchesBlock(key, value *yaml.Node, searches map[string]string, indent int) (string, error) {
+	if len(searches) == 0 {
+		return "", nil
+	}
+	if value.Kind != yaml.MappingNode {
+		value = &yaml.Node{Kind: yaml.MappingNode}
+	}
+
+	seen := make(map[string]bool)
+	var content []*yaml.Node
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		name, query := value.Content[i], value.Content[i+1]
+		updated, ok := searches[name.Value]
+		if !ok || seen[name.Value] {
+			continue
+		}
+		seen[name.Value] = true
+		if query.Kind != yaml.ScalarNode || query.Value != updated {
+			query = &yaml.Node{Kind: yaml.ScalarNode, Value: updated}
+		}
+		content = append(content, name, query)
+	}
+
+	var added []string
+	for name := range searches {
+		if !seen[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, &yaml.Node{Kind: yaml.ScalarNode, Value: searches[name]})
+	}
+	value.Content = content
+
+	// Comments above the key stay in the file as they are
+	section := *key
+	section.HeadComment = ""
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{&section, value}}
+
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(mapping); err != nil {
+		return "", fmt.Errorf("could not format saved searches: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("could not format saved searches: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// indentOf returns the indentation used for nested mappings in the file,
+// defaulting to two spaces
+func indentOf(root *yaml.Node) int {
+	if root == nil {
+		return 2
+	}
+	for i := 1; i < len(root.Content); i += 2 {
+		nested := root.Content[i]
+		if nested.Kind == yaml.MappingNode && nested.Style&yaml.FlowStyle == 0 && len(nested.Content) > 0 {
+			if indent := nested.Content[0].Column - root.Content[i-1].Column; indent > 0 {
+				return indent
+			}
+		}
+	}
+	return 2
+}
+
+func isBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || (strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(line, " "))
+}
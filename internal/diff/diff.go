@@ -0,0 +1,236 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpKind identifies the type of a diff operation
+type OpKind int
+
+const (
+	Equal OpKind = iota
+	Insert
+	Delete
+)
+
+// Op is a single line of a line-based diff
+type Op struct {
+	Kind OpKind
+	Text string
+}
+
+// Lines computes the shortest edit script between a and b using
+// Myers' algorithm
+func Lines(a, b []string) []Op {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset, d)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack walks the recorded frontiers from the end to build the script
+func backtrack(a, b []string, trace [][]int, offset, d int) []Op {
+	x, y := len(a), len(b)
+	var ops []Op
+
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, Op{Kind: Equal, Text: a[x]})
+		}
+
+		if x == prevX {
+			y--
+			ops = append(ops, Op{Kind: Insert, Text: b[y]})
+		} else {
+			x--
+			ops = append(ops, Op{Kind: Delete, Text: a[x]})
+		}
+	}
+
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, Op{Kind: Equal, Text: a[x]})
+	}
+
+	// Reverse into forward order
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// Unified returns a unified diff of two texts, or an empty string if they
+// are identical. context is the number of unchanged lines around each hunk.
+func Unified(fromName, toName, from, to string, context int) string {
+	ops := Lines(splitLines(from), splitLines(to))
+
+	changed := false
+	for _, op := range ops {
+		if op.Kind != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", fromName, toName)
+
+	for _, h := range hunks(ops, context) {
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(h.fromLine, h.fromCount), hunkRange(h.toLine, h.toCount))
+		for _, op := range h.ops {
+			switch op.Kind {
+			case Equal:
+				buf.WriteString(" ")
+			case Insert:
+				buf.WriteString("+")
+			case Delete:
+				buf.WriteString("-")
+			}
+			buf.WriteString(op.Text)
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.String()
+}
+
+type hunk struct {
+	fromLine, fromCount int
+	toLine, toCount     int
+	ops                 []Op
+}
+
+// hunks groups changes with up to context lines of surrounding text
+func hunks(ops []Op, context int) []hunk {
+	var result []hunk
+
+	// Line numbers (1-based) at each op index
+	fromLine, toLine := 1, 1
+	fromAt := make([]int, len(ops))
+	toAt := make([]int, len(ops))
+	for i, op := range ops {
+		fromAt[i], toAt[i] = fromLine, toLine
+		if op.Kind != Insert {
+			fromLine++
+		}
+		if op.Kind != Delete {
+			toLine++
+		}
+	}
+
+	i := 0
+	for i < len(ops) {
+		if ops[i].Kind == Equal {
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk while changes are within 2*context lines of each other
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != Equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].Kind == Equal {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		h := hunk{fromLine: fromAt[start], toLine: toAt[start], ops: ops[start:end]}
+		for _, op := range h.ops {
+			if op.Kind != Insert {
+				h.fromCount++
+			}
+			if op.Kind != Delete {
+				h.toCount++
+			}
+		}
+		result = append(result, h)
+		i = end
+	}
+
+	return result
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		// An empty range refers to the line before the change
+		return fmt.Sprintf("%d,0", line-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
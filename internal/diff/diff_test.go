@@ -0,0 +1,96 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{"", "", 0},
+		{"a", "", 1},
+		{"", "a b", 2},
+		{"a b c", "a b c", 0},
+		{"a b c a b b a", "c b a b a c", 5},
+		{"a b c d", "a x c d", 2},
+		{"x a b c", "a b c y", 2},
+		{"a b c d e f", "f e d c b a", 10},
+	}
+
+	for _, tt := range tests {
+		a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+		ops := Lines(a, b)
+
+		var from, to []string
+		edits := 0
+		for _, op := range ops {
+			if op.Kind != Insert {
+				from = append(from, op.Text)
+			}
+			if op.Kind != Delete {
+				to = append(to, op.Text)
+			}
+			if op.Kind != Equal {
+				edits++
+			}
+		}
+
+		if strings.Join(from, " ") != tt.a || strings.Join(to, " ") != tt.b {
+			t.Errorf("Lines(%q, %q) = %v does not turn one into the other", tt.a, tt.b, ops)
+		}
+		if edits != tt.edits {
+			t.Errorf("Lines(%q, %q) has %d edits, want %d", tt.a, tt.b, edits, tt.edits)
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	from := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	to := "one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	want := `--- a/n.md
++++ b/n.md
+@@ -1,3 +1,3 @@
+ one
+-two
++2
+ three
+@@ -10 +10,2 @@
+ ten
++eleven
+`
+	if got := Unified("a/n.md", "b/n.md", from, to, 1); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Unified("a", "b", from, from, 3); got != "" {
+		t.Errorf("Unified() of equal texts = %q, want empty", got)
+	}
+
+	// Changes closer than twice the context share a hunk
+	want = `--- a
++++ b
+@@ -1,4 +1,4 @@
+-one
++1
+ two
+ three
+-four
++4
+`
+	if got := Unified("a", "b", "one\ntwo\nthree\nfour\n", "1\ntwo\nthree\n4\n", 1); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+
+	want = `--- a
++++ b
+@@ -0,0 +1 @@
++new
+`
+	if got := Unified("a", "b", "", "new\n", 3); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,493 @@
+package doctor
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/git"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/pkg/slug"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is the result of a single diagnostic check
+type Check struct {
+	Name    string   `json:"name"`
+	Status  Status   `json:"status"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+}
+
+// Summary counts checks by status
+type Summary struct {
+	OK   int `json:"ok"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
+}
+
+// Report is the result of running all checks
+type Report struct {
+	Checks  []Check `json:"checks"`
+	Summary Summary `json:"summary"`
+}
+
+// Healthy reports whether no check failed
+func (r *Report) Healthy() bool {
+	return r.Summary.Fail == 0
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+	switch c.Status {
+	case StatusOK:
+		r.Summary.OK++
+	case StatusWarn:
+		r.Summary.Warn++
+	case StatusFail:
+		r.Summary.Fail++
+	}
+}
+
+// Run performs all checks. configErr is the error returned when loading
+// the configuration, if any.
+func Run(configErr error) *Report {
+	report := &Report{}
+
+	report.add(checkConfig(configErr))
+
+	dirCheck := checkNotesDir()
+	report.add(dirCheck)
+	if dirCheck.Status == StatusFail {
+		// Nothing else can be checked without a notes directory
+		return report
+	}
+
+	report.add(checkGit())
+	report.add(checkRemotes())
+
+	docs, err := notes.LoadDocuments(config.Get().NotesDir)
+	if err != nil {
+		report.add(Check{Name: "notes", Status: StatusFail, Message: err.Error()})
+		return report
+	}
+
+	report.add(checkFrontmatter(docs))
+	report.add(checkDuplicateTitles(docs))
+	report.add(checkDuplicateIDs(docs))
+	report.add(checkAliasCollisions(docs))
+	report.add(checkSlugMismatch(docs))
+	report.add(checkSlugCollisions(docs))
+
+	return report
+}
+
+func checkConfig(configErr error) Check {
+	if configErr != nil {
+		return Check{
+			Name:    "config",
+			Status:  StatusFail,
+			Message: fmt.Sprintf("could not load config: %v (using defaults)", configErr),
+		}
+	}
+
+	return Check{Name: "config", Status: StatusOK, Message: "config loaded"}
+}
+
+func checkNotesDir() Check {
+	dir := config.Get().NotesDir
+	c := Check{Name: "notes_dir"}
+
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%s does not exist (run 'noti init')", dir)
+		return c
+	}
+	if err != nil {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("could not access %s: %v", dir, err)
+		return c
+	}
+	if !info.IsDir() {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%s is not a directory", dir)
+		return c
+	}
+
+	if _, err := os.ReadDir(dir); err != nil {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%s is not readable: %v", dir, err)
+		return c
+	}
+
+	probe, err := os.CreateTemp(dir, ".noti-doctor-*")
+	if err != nil {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%s is not writable: %v", dir, err)
+		return c
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	c.Status = StatusOK
+	c.Message = fmt.Sprintf("%s is readable and writable", dir)
+	return c
+}
+
+func checkGit() Check {
+	cfg := config.Get()
+	c := Check{Name: "git"}
+
+	if cfg.GitBackend == git.BackendGo {
+		c.Status = StatusOK
+		c.Message = "using the built-in git backend"
+	} else if bin, err := exec.LookPath("git"); err != nil {
+		c.Status = StatusFail
+		c.Message = "git executable not found in PATH (install git or set git_backend: go)"
+		return c
+	} else {
+		c.Status = StatusOK
+		c.Message = fmt.Sprintf("git found at %s", bin)
+	}
+
+	if !git.IsGitRepo() {
+		c.Status = StatusWarn
+		c.Message += "; notes directory is not a git repository (run 'noti git init')"
+	}
+
+	return c
+}
+
+func checkRemotes() Check {
+	c := Check{Name: "remotes"}
+
+	if !git.IsGitRepo() {
+		c.Status = StatusOK
+		c.Message = "skipped (not a git repository)"
+		return c
+	}
+
+	remotes, err := git.Remotes()
+	if err != nil {
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("could not list remotes: %v", err)
+		return c
+	}
+	if len(remotes) == 0 {
+		c.Status = StatusOK
+		c.Message = "no remotes configured"
+		return c
+	}
+
+	checked, unreachable := 0, 0
+	for _, remote := range remotes {
+		dir, ok := localRemotePath(remote.URL)
+		if !ok {
+			c.Details = append(c.Details, fmt.Sprintf("%s: %s (not checked, network remote)", remote.Name, remote.URL))
+			continue
+		}
+
+		checked++
+		if !isRepository(dir) {
+			unreachable++
+			c.Details = append(c.Details, fmt.Sprintf("%s: %s is not a reachable git repository", remote.Name, remote.URL))
+		}
+	}
+
+	switch {
+	case unreachable > 0:
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%d of %d local remote(s) unreachable", unreachable, checked)
+	case checked == 0:
+		c.Status = StatusOK
+		c.Message = fmt.Sprintf("%d remote(s), none local", len(remotes))
+	default:
+		c.Status = StatusOK
+		c.Message = fmt.Sprintf("%d local remote(s) reachable", checked)
+	}
+
+	return c
+}
+
+// localRemotePath returns the directory of a remote on the local file
+// system, or false for network remotes
+func localRemotePath(remoteURL string) (string, bool) {
+	if strings.HasPrefix(remoteURL, "file://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", false
+		}
+		return filepath.FromSlash(u.Path), true
+	}
+
+	if strings.Contains(remoteURL, "://") {
+		return "", false
+	}
+
+	// scp-like syntax such as git@host:repo.git
+	if i := strings.Index(remoteURL, ":"); i > 0 && !strings.Contains(remoteURL[:i], "/") && !filepath.IsAbs(remoteURL) {
+		return "", false
+	}
+
+	dir := remoteURL
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(config.Get().NotesDir, dir)
+	}
+	return dir, true
+}
+
+// isRepository reports whether dir is a bare or non-bare git repository
+func isRepository(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(dir, "HEAD"))
+	return err == nil
+}
+
+func checkFrontmatter(docs []*notes.Document) Check {
+	c := Check{Name: "frontmatter"}
+
+	for _, doc := range docs {
+		if doc.ParseErr != nil {
+			c.Details = append(c.Details, fmt.Sprintf("%s: %v", doc.Path, doc.ParseErr))
+		}
+	}
+
+	if len(c.Details) > 0 {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%d note(s) have invalid frontmatter", len(c.Details))
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = fmt.Sprintf("%d note(s) parsed", len(docs))
+	return c
+}
+
+func checkDuplicateTitles(docs []*notes.Document) Check {
+	c := Check{Name: "duplicate-titles"}
+
+	byTitle := make(map[string][]string)
+	for _, doc := range docs {
+		if doc.Frontmatter == nil || strings.TrimSpace(doc.Frontmatter.Title) == "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(doc.Frontmatter.Title))
+		byTitle[key] = append(byTitle[key], doc.Slug())
+	}
+
+	for _, group := range sortedGroups(byTitle) {
+		c.Details = append(c.Details, fmt.Sprintf("%q: %s", group.key, strings.Join(group.slugs, ", ")))
+	}
+
+	if len(c.Details) > 0 {
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("%d title(s) used by more than one note", len(c.Details))
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = "all titles are unique"
+	return c
+}
+
+func checkDuplicateIDs(docs []*notes.Document) Check {
+	c := Check{Name: "duplicate-ids"}
+
+	byID := make(map[string][]string)
+	for _, doc := range docs {
+		if doc.Frontmatter == nil || doc.Frontmatter.ID == "" {
+			continue
+		}
+		byID[doc.Frontmatter.ID] = append(byID[doc.Frontmatter.ID], doc.Slug())
+	}
+
+	for _, group := range sortedGroups(byID) {
+		c.Details = append(c.Details, fmt.Sprintf("%s: %s", group.key, strings.Join(group.slugs, ", ")))
+	}
+
+	if len(c.Details) > 0 {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%d id(s) used by more than one note", len(c.Details))
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = "all ids are unique"
+	return c
+}
+
+func checkAliasCollisions(docs []*notes.Document) Check {
+	c := Check{Name: "alias-collisions"}
+
+	// Names other notes can already be found by
+	names := make(map[string][]string)
+	byAlias := make(map[string][]string)
+	for _, doc := range docs {
+		slug := doc.Slug()
+		names[strings.ToLower(slug)] = append(names[strings.ToLower(slug)], slug)
+		if base := strings.ToLower(path.Base(slug)); base != strings.ToLower(slug) {
+			names[base] = append(names[base], slug)
+		}
+		if doc.Frontmatter == nil {
+			continue
+		}
+		if title := strings.ToLower(strings.TrimSpace(doc.Frontmatter.Title)); title != "" {
+			names[title] = append(names[title], slug)
+		}
+		for _, alias := range doc.Frontmatter.Aliases {
+			if key := strings.ToLower(strings.TrimSpace(alias)); key != "" {
+				byAlias[key] = append(byAlias[key], slug)
+			}
+		}
+	}
+
+	for _, group := range sortedGroups(byAlias) {
+		c.Details = append(c.Details, fmt.Sprintf("alias %q is used by %s", group.key, strings.Join(group.slugs, ", ")))
+	}
+
+	aliases := make([]string, 0, len(byAlias))
+	for alias := range byAlias {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		seen := make(map[string]bool)
+		for _, other := range names[alias] {
+			if seen[other] {
+				continue
+			}
+			seen[other] = true
+			for _, owner := range byAlias[alias] {
+				if owner != other {
+					c.Details = append(c.Details, fmt.Sprintf("alias %q of %s matches the name or title of %s", alias, owner, other))
+				}
+			}
+		}
+	}
+
+	if len(c.Details) > 0 {
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("%d alias collision(s)", len(c.Details))
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = "no alias collisions"
+	return c
+}
+
+func checkSlugMismatch(docs []*notes.Document) Check {
+	c := Check{Name: "slug-mismatch"}
+
+	maxLength := config.Get().SlugMaxLength
+	if maxLength <= 0 {
+		maxLength = slug.DefaultMaxLength
+	}
+
+	for _, doc := range docs {
+		if doc.Frontmatter == nil || strings.TrimSpace(doc.Frontmatter.Title) == "" {
+			continue
+		}
+
+		base := path.Base(doc.Slug())
+		name := slug.TitlePart(base)
+		// Account for the prefix when applying the configured length limit
+		expected := slug.Truncate(slug.Make(doc.Frontmatter.Title), maxLength-(len(base)-len(name)))
+		if name == expected || isSuffixed(name, expected) {
+			continue
+		}
+
+		c.Details = append(c.Details, fmt.Sprintf("%s: title %q suggests %q", doc.Path, doc.Frontmatter.Title, expected))
+	}
+
+	if len(c.Details) > 0 {
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("%d file name(s) do not match their title", len(c.Details))
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = "file names match titles"
+	return c
+}
+
+// isSuffixed reports whether name is base with a numeric -N suffix, as
+// given to notes whose slug was already taken
+func isSuffixed(name, base string) bool {
+	rest, ok := strings.CutPrefix(name, base+"-")
+	if !ok || rest == "" {
+		return false
+	}
+	for _, r := range rest {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func checkSlugCollisions(docs []*notes.Document) Check {
+	c := Check{Name: "slug-collisions"}
+
+	bySlug := make(map[string][]string)
+	for _, doc := range docs {
+		slug := doc.Slug()
+		key := strings.ToLower(slug)
+		bySlug[key] = append(bySlug[key], slug)
+	}
+
+	for _, group := range sortedGroups(bySlug) {
+		c.Details = append(c.Details, strings.Join(group.slugs, ", "))
+	}
+
+	if len(c.Details) > 0 {
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("%d slug(s) differ only in case and collide on case-insensitive file systems", len(c.Details))
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = "no case-insensitive slug collisions"
+	return c
+}
+
+type group struct {
+	key   string
+	slugs []string
+}
+
+// sortedGroups returns the entries with more than one slug, sorted by key
+func sortedGroups(m map[string][]string) []group {
+	var groups []group
+	for key, slugs := range m {
+		if len(slugs) < 2 {
+			continue
+		}
+		sort.Strings(slugs)
+		groups = append(groups, group{key: key, slugs: slugs})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].key < groups[j].key
+	})
+
+	return groups
+}
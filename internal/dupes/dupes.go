@@ -0,0 +1,401 @@
+package dupes
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devjasha/noti-vim/internal/diff"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/search"
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+)
+
+const (
+	// DefaultThreshold is the similarity above which notes are near
+	// duplicates
+	DefaultThreshold = 0.8
+
+	// shingleSize is the number of words in a shingle
+	shingleSize = 3
+
+	// The MinHash signature is split into bands of rows for locality
+	// sensitive hashing: notes agreeing on all rows of any band are compared.
+	// 32 bands of 4 rows catch pairs down to about 40% similarity.
+	bands         = 32
+	rowsPerBand   = 4
+	signatureSize = bands * rowsPerBand
+)
+
+// Group is a set of duplicate notes, oldest first
+type Group struct {
+	Docs []*notes.Document
+	// Exact is set when all notes have the same content
+	Exact bool
+	// Similarity is the lowest Jaccard similarity of word shingles between
+	// notes that were grouped together, 1 for exact duplicates
+	Similarity float64
+}
+
+// note is a document prepared for comparison
+type note struct {
+	doc       *notes.Document
+	hash      [sha256.Size]byte
+	shingles  map[uint64]bool
+	signature [signatureSize]uint64
+}
+
+// Find groups notes with the same content, and notes whose word shingles
+// have a Jaccard similarity of at least threshold. Notes without content
+// and notes whose frontmatter cannot be parsed are ignored.
+func Find(docs []*notes.Document, threshold float64) []Group {
+	var prepared []*note
+	for _, doc := range docs {
+		if doc.ParseErr != nil {
+			continue
+		}
+		text := normalize(doc.Content)
+		if text == "" {
+			continue
+		}
+
+		n := &note{doc: doc, hash: sha256.Sum256([]byte(text)), shingles: shingles(text)}
+		n.signature = minHash(n.shingles)
+		prepared = append(prepared, n)
+	}
+
+	uf := newUnionFind(len(prepared))
+
+	// Exact duplicates
+	byHash := make(map[[sha256.Size]byte]int)
+	for i, n := range prepared {
+		if j, ok := byHash[n.hash]; ok {
+			uf.union(i, j, 1)
+		} else {
+			byHash[n.hash] = i
+		}
+	}
+
+	// Near duplicates: candidates share a band of their signatures, and are
+	// confirmed with the exact similarity of their shingles
+	checked := make(map[[2]int]bool)
+	for b := 0; b < bands; b++ {
+		buckets := make(map[uint64][]int)
+		for i, n := range prepared {
+			key := bandKey(n.signature[b*rowsPerBand : (b+1)*rowsPerBand])
+			buckets[key] = append(buckets[key], i)
+		}
+
+		for _, bucket := range buckets {
+			for x := 0; x < len(bucket); x++ {
+				for y := x + 1; y < len(bucket); y++ {
+					i, j := bucket[x], bucket[y]
+					if checked[[2]int{i, j}] || prepared[i].hash == prepared[j].hash {
+						continue
+					}
+					checked[[2]int{i, j}] = true
+
+					if sim := jaccard(prepared[i].shingles, prepared[j].shingles); sim >= threshold {
+						uf.union(i, j, sim)
+					}
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range prepared {
+		root := uf.find(i)
+		members[root] = append(members[root], i)
+	}
+
+	var groups []Group
+	for root, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		group := Group{Exact: true, Similarity: uf.similarity[root]}
+		for _, i := range indexes {
+			group.Docs = append(group.Docs, prepared[i].doc)
+			if prepared[i].hash != prepared[indexes[0]].hash {
+				group.Exact = false
+			}
+		}
+		if group.Exact {
+			group.Similarity = 1
+		}
+
+		sort.SliceStable(group.Docs, func(i, j int) bool {
+			a, b := created(group.Docs[i]), created(group.Docs[j])
+			if !a.Equal(b) {
+				return a.Before(b)
+			}
+			return group.Docs[i].Path < group.Docs[j].Path
+		})
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Similarity != groups[j].Similarity {
+			return groups[i].Similarity > groups[j].Similarity
+		}
+		return groups[i].Docs[0].Path < groups[j].Docs[0].Path
+	})
+
+	return groups
+}
+
+func created(doc *notes.Document) time.Time {
+	if doc.Frontmatter == nil {
+		return time.Time{}
+	}
+	return doc.Frontmatter.Created
+}
+
+// Merge folds the duplicates into keep: keep gains their tags, and their
+// slugs, file names, ids, titles and aliases become aliases of keep so
+// wikilinks to them still resolve. Lines of a duplicate that keep does not
+// have are appended to its content under a heading naming the duplicate,
+// so merging near duplicates loses nothing. Merge returns the number of
+// lines appended from each duplicate, by slug.
+func Merge(keep *notes.Document, duplicates []*notes.Document) map[string]int {
+	if keep.Frontmatter == nil {
+		keep.Frontmatter = &frontmatter.Frontmatter{Title: path.Base(keep.Slug())}
+	}
+	fm := keep.Frontmatter
+	appended := make(map[string]int)
+
+	for _, doc := range duplicates {
+		if doc == keep {
+			continue
+		}
+
+		aliases := []string{doc.Slug(), path.Base(doc.Slug())}
+		if doc.Frontmatter != nil {
+			fm.Tags = appendMissing(fm.Tags, doc.Frontmatter.Tags...)
+			aliases = append(aliases, doc.Frontmatter.ID, doc.Frontmatter.Title)
+			aliases = append(aliases, doc.Frontmatter.Aliases...)
+		}
+		for _, alias := range aliases {
+			if alias != "" && !strings.EqualFold(alias, keep.Slug()) && !strings.EqualFold(alias, fm.Title) {
+				fm.Aliases = appendMissing(fm.Aliases, alias)
+			}
+		}
+
+		appended[doc.Slug()] = appendMissingLines(keep, doc)
+	}
+
+	return appended
+}
+
+// appendMissingLines appends the runs of lines that doc has and keep lacks
+// to the content of keep, and returns how many lines it appended
+func appendMissingLines(keep, doc *notes.Document) int {
+	kept := strings.Split(normalize(keep.Content), "\n")
+	other := strings.Split(normalize(doc.Content), "\n")
+
+	var runs []string
+	var run []string
+	count := 0
+	flush := func() {
+		// Blank lines at the edges of a run only separated it from the
+		// lines keep has
+		for len(run) > 0 && run[0] == "" {
+			run = run[1:]
+		}
+		for len(run) > 0 && run[len(run)-1] == "" {
+			run = run[:len(run)-1]
+		}
+		if len(run) > 0 {
+			runs = append(runs, strings.Join(run, "\n"))
+			count += len(run)
+		}
+		run = nil
+	}
+
+	for _, op := range diff.Lines(kept, other) {
+		switch op.Kind {
+		case diff.Insert:
+			run = append(run, op.Text)
+		case diff.Equal:
+			flush()
+		}
+	}
+	flush()
+
+	if len(runs) == 0 {
+		return 0
+	}
+
+	title := doc.Slug()
+	if doc.Frontmatter != nil && doc.Frontmatter.Title != "" {
+		title = fmt.Sprintf("%s (%s)", doc.Frontmatter.Title, doc.Slug())
+	}
+	keep.Content = strings.TrimRight(keep.Content, "\n") + "\n\n## Merged from " + title + "\n\n" + strings.Join(runs, "\n\n")
+	return count
+}
+
+func appendMissing(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// normalize drops trailing whitespace and surrounding blank lines, so
+// notes differing only in those count as exact duplicates
+func normalize(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// shingles hashes every run of shingleSize consecutive words. Shorter texts
+// form a single shingle.
+func shingles(text string) map[uint64]bool {
+	var words []string
+	for _, tok := range search.Tokenize(text) {
+		words = append(words, tok.Text)
+	}
+
+	set := make(map[uint64]bool)
+	if len(words) < shingleSize {
+		set[hashWords(words)] = true
+		return set
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		set[hashWords(words[i:i+shingleSize])] = true
+	}
+	return set
+}
+
+func hashWords(words []string) uint64 {
+	h := fnv.New64a()
+	for _, w := range words {
+		h.Write([]byte(w))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// minHash returns the smallest value of each of signatureSize hash
+// functions over the shingles. The share of equal positions in two
+// signatures estimates the Jaccard similarity of the sets.
+func minHash(set map[uint64]bool) [signatureSize]uint64 {
+	var sig [signatureSize]uint64
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+
+	for shingle := range set {
+		for i := range sig {
+			if h := mix(shingle ^ seeds[i]); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+// seeds derive the hash functions of the signature
+var seeds = func() [signatureSize]uint64 {
+	var s [signatureSize]uint64
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range s {
+		x = mix(x + uint64(i))
+		s[i] = x
+	}
+	return s
+}()
+
+// mix is the splitmix64 finalizer
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func bandKey(rows []uint64) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, r := range rows {
+		binary.LittleEndian.PutUint64(buf[:], r)
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// jaccard is the size of the intersection of a and b over their union
+func jaccard(a, b map[uint64]bool) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for x := range a {
+		if b[x] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// unionFind joins notes into groups, tracking the lowest similarity that
+// joined each group
+type unionFind struct {
+	parent     []int
+	similarity map[int]float64
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n), similarity: make(map[int]float64)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+func (uf *unionFind) find(i int) int {
+	for uf.parent[i] != i {
+		uf.parent[i] = uf.parent[uf.parent[i]]
+		i = uf.parent[i]
+	}
+	return i
+}
+
+func (uf *unionFind) union(i, j int, sim float64) {
+	a, b := uf.find(i), uf.find(j)
+
+	lowest := sim
+	for _, root := range []int{a, b} {
+		if s, ok := uf.similarity[root]; ok && s < lowest {
+			lowest = s
+		}
+	}
+	delete(uf.similarity, a)
+	delete(uf.similarity, b)
+
+	if a != b {
+		uf.parent[a] = b
+	}
+	uf.similarity[b] = lowest
+}
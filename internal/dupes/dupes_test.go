@@ -0,0 +1,112 @@
+package dupes
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+func doc(t *testing.T, path, data string) *notes.Document {
+	t.Helper()
+	d := notes.NewDocument(path, []byte(data))
+	if d.ParseErr != nil {
+		t.Fatalf("NewDocument(%s): %v", path, d.ParseErr)
+	}
+	return d
+}
+
+// words returns n distinct words starting at word start
+func words(start, n int) string {
+	w := make([]string, n)
+	for i := range w {
+		w[i] = fmt.Sprintf("w%d", start+i)
+	}
+	return strings.Join(w, " ")
+}
+
+func TestMinHashEstimatesSimilarity(t *testing.T) {
+	// 100 words shared out of 120 in each text
+	a := shingles(words(0, 120))
+	b := shingles(words(20, 120))
+	exact := jaccard(a, b)
+
+	sigA, sigB := minHash(a), minHash(b)
+	same := 0
+	for i := range sigA {
+		if sigA[i] == sigB[i] {
+			same++
+		}
+	}
+	estimate := float64(same) / signatureSize
+
+	if d := estimate - exact; d < -0.15 || d > 0.15 {
+		t.Errorf("MinHash estimate %.2f is far from the Jaccard similarity %.2f", estimate, exact)
+	}
+	if jaccard(a, a) != 1 {
+		t.Errorf("jaccard of a set with itself = %v, want 1", jaccard(a, a))
+	}
+}
+
+func TestFind(t *testing.T) {
+	base := words(0, 200)
+	docs := []*notes.Document{
+		doc(t, "b.md", "---\ntitle: B\ncreated: 2024-02-01T00:00:00Z\n---\n\n"+base+"  \n\n"),
+		doc(t, "a.md", "---\ntitle: A\ncreated: 2024-01-01T00:00:00Z\n---\n\n"+base),
+		doc(t, "near.md", "---\ntitle: Near\n---\n\n"+base+" "+words(1000, 5)),
+		doc(t, "other.md", "---\ntitle: Other\n---\n\n"+words(500, 200)),
+		doc(t, "other-copy.md", "---\ntitle: Other copy\n---\n\n"+words(500, 200)),
+		doc(t, "empty.md", "---\ntitle: Empty\n---\n"),
+		doc(t, "empty2.md", "---\ntitle: Empty too\n---\n"),
+		notes.NewDocument("broken.md", []byte("---\ntitle: [\n---\n\n"+base)),
+	}
+
+	var got []string
+	for _, g := range Find(docs, 0.9) {
+		var slugs []string
+		for _, d := range g.Docs {
+			slugs = append(slugs, d.Slug())
+		}
+		got = append(got, fmt.Sprintf("%s exact=%v", strings.Join(slugs, " "), g.Exact))
+	}
+
+	// Near joins the group of a and b; a is older than b, and notes
+	// without a created date sort first
+	want := []string{
+		"other-copy other exact=true",
+		"near a b exact=false",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Find() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if groups := Find(docs, 1); len(groups) != 2 || !groups[1].Exact {
+		t.Errorf("Find() at threshold 1 = %d groups, want the two exact groups", len(groups))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	keep := doc(t, "notes/trip.md", "---\ntitle: Trip\ntags:\n    - travel\n---\n\nPack\nBook hotel\nLeave")
+	near := doc(t, "old/trip-plan.md", "---\nid: 20240101\ntitle: Trip plan\ntags:\n    - Travel\n    - plans\naliases:\n    - journey\n---\n\nPack\nBuy tickets\n\nBook hotel\nLeave\nCall mum")
+	copied := doc(t, "trip.md", "---\ntitle: trip\n---\n\nPack\nBook hotel\nLeave")
+
+	appended := Merge(keep, []*notes.Document{keep, near, copied})
+
+	if appended["old/trip-plan"] != 2 || appended["trip"] != 0 {
+		t.Errorf("Merge() appended %v, want 2 lines from old/trip-plan and none from trip", appended)
+	}
+
+	fm := keep.Frontmatter
+	if got, want := strings.Join(fm.Tags, ","), "travel,plans"; got != want {
+		t.Errorf("tags = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(fm.Aliases, ","), "old/trip-plan,trip-plan,20240101,Trip plan,journey"; got != want {
+		t.Errorf("aliases = %s, want %s", got, want)
+	}
+
+	want := "Pack\nBook hotel\nLeave\n\n## Merged from Trip plan (old/trip-plan)\n\nBuy tickets\n\nCall mum"
+	if keep.Content != want {
+		t.Errorf("content =\n%s\nwant\n%s", keep.Content, want)
+	}
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/devjasha/noti-vim/internal/config"
+)
+
+// Backend performs git operations on the repository in a single directory
+type Backend interface {
+	// Init creates a new repository
+	Init() error
+	// Clone clones the repository at url
+	Clone(url string) error
+	// Status returns the working tree status in porcelain format
+	Status() (string, error)
+	// StatusShort returns the status like git status --short --branch: a
+	// "## branch...upstream [ahead n, behind m]" line, then one line per file
+	StatusShort() (string, error)
+	// Commit stages the given paths (or all changes if none are given)
+	// and commits them
+	Commit(message string, paths ...string) error
+	// Log returns up to maxCount commits, one per line (0 for all)
+	Log(maxCount int) (string, error)
+	// Pull fetches and merges changes from the remote
+	Pull() error
+	// Push pushes commits to the remote
+	Push() error
+	// Diff returns a unified diff of unstaged changes, optionally limited
+	// to the given paths
+	Diff(paths ...string) (string, error)
+	// StagedFiles lists files added, copied, modified, or renamed in the index
+	StagedFiles() ([]string, error)
+	// StagedContent returns the content of a file as staged in the index
+	StagedContent(path string) ([]byte, error)
+	// Blame returns the commit that last changed each line of a file
+	Blame(path string) ([]BlameLine, error)
+	// HasRemote reports whether a remote is configured
+	HasRemote() (bool, error)
+	// Remotes lists the configured remotes
+	Remotes() ([]Remote, error)
+	// AddRemote adds a remote with the given name and url
+	AddRemote(name, url string) error
+	// RemoveRemote removes the named remote
+	RemoveRemote(name string) error
+}
+
+// Remote is a configured git remote
+type Remote struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+const (
+	// BackendExec shells out to the git binary
+	BackendExec = "exec"
+	// BackendGo uses a pure-Go git implementation
+	BackendGo = "go"
+)
+
+// New returns the backend of the given kind for the repository in dir
+func New(kind, dir string) (Backend, error) {
+	switch kind {
+	case "", BackendExec:
+		return NewExecBackend(dir), nil
+	case BackendGo:
+		return NewGoBackend(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown git backend %q (use %q or %q)", kind, BackendExec, BackendGo)
+	}
+}
+
+// Default returns the configured backend for the notes directory
+func Default() (Backend, error) {
+	cfg := config.Get()
+	return New(cfg.GitBackend, cfg.NotesDir)
+}
@@ -0,0 +1,331 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The tests run every scenario against both backends and compare what they
+// leave behind, using the git binary to set up and inspect repositories.
+
+func setupGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+
+	config := "[user]\n\tname = Test\n\temail = test@example.com\n[init]\n\tdefaultBranch = master\n"
+	if err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return string(output)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+var backendKinds = []string{BackendExec, BackendGo}
+
+// eachBackend runs fn with a fresh directory and backend of every kind and
+// returns what fn returned for each, keyed by kind
+func eachBackend(t *testing.T, fn func(t *testing.T, dir string, b Backend) string) map[string]string {
+	results := make(map[string]string)
+	for _, kind := range backendKinds {
+		t.Run(kind, func(t *testing.T) {
+			dir := t.TempDir()
+			b, err := New(kind, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results[kind] = fn(t, dir, b)
+		})
+	}
+	return results
+}
+
+func expectSame(t *testing.T, what string, results map[string]string) {
+	t.Helper()
+	if results[BackendExec] != results[BackendGo] {
+		t.Errorf("%s differs between backends\nexec:\n%s\ngo:\n%s", what, results[BackendExec], results[BackendGo])
+	}
+}
+
+func TestStatusParity(t *testing.T) {
+	setupGit(t)
+
+	results := eachBackend(t, func(t *testing.T, dir string, b Backend) string {
+		if err := b.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		empty, err := b.StatusShort()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "## No commits yet on master\n"; empty != want {
+			t.Errorf("StatusShort() on empty repo = %q, want %q", empty, want)
+		}
+
+		writeFile(t, dir, "a.md", "a\n")
+		writeFile(t, dir, "b.md", "b\n")
+		gitCmd(t, dir, "add", ".")
+		gitCmd(t, dir, "commit", "-q", "-m", "initial")
+
+		writeFile(t, dir, "a.md", "changed\n")
+		writeFile(t, dir, "c.md", "new\n")
+		writeFile(t, dir, "b.md", "staged\n")
+		gitCmd(t, dir, "add", "b.md")
+
+		status, err := b.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		short, err := b.StatusShort()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return status + short
+	})
+
+	expectSame(t, "status", results)
+	if want := "## master\n M a.md\nM  b.md\n?? c.md\n"; !strings.HasSuffix(results[BackendGo], want) {
+		t.Errorf("StatusShort() = %q, want suffix %q", results[BackendGo], want)
+	}
+}
+
+func TestCommitParity(t *testing.T) {
+	setupGit(t)
+
+	results := eachBackend(t, func(t *testing.T, dir string, b Backend) string {
+		if err := b.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile(t, dir, "a.md", "a\n")
+		writeFile(t, dir, "b.md", "b\n")
+		writeFile(t, dir, "gone.md", "gone\n")
+		if err := b.Commit("initial"); err != nil {
+			t.Fatal(err)
+		}
+
+		// b.md is staged beforehand and must stay out of a commit of a.md
+		// and gone.md, but stay staged
+		writeFile(t, dir, "a.md", "a2\n")
+		writeFile(t, dir, "b.md", "b2\n")
+		gitCmd(t, dir, "add", "b.md")
+		if err := os.Remove(filepath.Join(dir, "gone.md")); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Commit("update a", "a.md", "gone.md"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := b.Commit("nothing"); err != nil {
+			// b.md is still staged, so committing everything succeeds
+			t.Fatal(err)
+		}
+		if err := b.Commit("again"); err == nil || !strings.Contains(err.Error(), "nothing to commit") {
+			t.Errorf("Commit() with no changes = %v, want nothing to commit", err)
+		}
+
+		return gitCmd(t, dir, "log", "--format=%s", "--name-status")
+	})
+
+	expectSame(t, "history", results)
+	want := "nothing\n\nM\tb.md\nupdate a\n\nM\ta.md\nD\tgone.md\ninitial\n\nA\ta.md\nA\tb.md\nA\tgone.md\n"
+	if results[BackendGo] != want {
+		t.Errorf("history = %q, want %q", results[BackendGo], want)
+	}
+}
+
+func TestCommitKeepsOtherStagedChanges(t *testing.T) {
+	setupGit(t)
+
+	results := eachBackend(t, func(t *testing.T, dir string, b Backend) string {
+		if err := b.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile(t, dir, "a.md", "a\n")
+		writeFile(t, dir, "b.md", "b\n")
+		writeFile(t, dir, "old.md", "old\n")
+		if err := b.Commit("initial"); err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile(t, dir, "a.md", "a2\n")
+		writeFile(t, dir, "b.md", "b2\n")
+		writeFile(t, dir, "b.md", "x\ny\nz\n")
+		writeFile(t, dir, "new.md", "new\n")
+		gitCmd(t, dir, "add", "b.md", "new.md")
+		gitCmd(t, dir, "rm", "-q", "old.md")
+		if err := b.Commit("update a", "a.md"); err != nil {
+			t.Fatal(err)
+		}
+
+		return gitCmd(t, dir, "status", "--porcelain") + gitCmd(t, dir, "show", "--format=", "--name-only", "HEAD")
+	})
+
+	expectSame(t, "status after a path-limited commit", results)
+	if want := "M  b.md\nA  new.md\nD  old.md\na.md\n"; results[BackendGo] != want {
+		t.Errorf("status and commit = %q, want %q", results[BackendGo], want)
+	}
+}
+
+// newRemote returns the path of a bare repository holding one commit
+func newRemote(t *testing.T) string {
+	t.Helper()
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	gitCmd(t, filepath.Dir(remote), "init", "-q", "--bare", remote)
+
+	seed := t.TempDir()
+	gitCmd(t, seed, "init", "-q")
+	writeFile(t, seed, "note.md", "one\n")
+	gitCmd(t, seed, "add", ".")
+	gitCmd(t, seed, "commit", "-q", "-m", "seed")
+	gitCmd(t, seed, "push", "-q", remote, "master")
+
+	return remote
+}
+
+func TestPushPullParity(t *testing.T) {
+	setupGit(t)
+
+	results := eachBackend(t, func(t *testing.T, dir string, _ Backend) string {
+		kind := filepath.Base(t.Name())
+		remote := newRemote(t)
+		mine, theirs := filepath.Join(dir, "mine"), filepath.Join(dir, "theirs")
+		a, _ := New(kind, mine)
+		b, _ := New(kind, theirs)
+
+		if err := a.Clone("file://" + remote); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Clone("file://" + remote); err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile(t, mine, "note.md", "one\ntwo\n")
+		if err := a.Commit("add two"); err != nil {
+			t.Fatal(err)
+		}
+
+		ahead, err := a.StatusShort()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.Push(); err != nil {
+			t.Fatal(err)
+		}
+		pushed, err := a.StatusShort()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := b.Pull(); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := readFile(t, theirs, "note.md"), "one\ntwo\n"; got != want {
+			t.Errorf("pulled note = %q, want %q", got, want)
+		}
+		if err := b.Pull(); err != nil {
+			t.Errorf("Pull() when up to date = %v", err)
+		}
+
+		return ahead + pushed + gitCmd(t, theirs, "log", "--format=%s")
+	})
+
+	expectSame(t, "push and pull", results)
+	want := "## master...origin/master [ahead 1]\n## master...origin/master\n"
+	if !strings.HasPrefix(results[BackendGo], want) {
+		t.Errorf("status around push = %q, want prefix %q", results[BackendGo], want)
+	}
+}
+
+func TestBlameParity(t *testing.T) {
+	setupGit(t)
+
+	results := eachBackend(t, func(t *testing.T, dir string, b Backend) string {
+		if err := b.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		writeFile(t, dir, "a.md", "---\ntitle: A\n---\n\none\ntwo\nthree\n")
+		writeFile(t, dir, "other.md", "other\n")
+		writeFile(t, dir, "b.md", "x\ny")
+		if _, err := b.Blame("a.md"); !errors.Is(err, ErrNotCommitted) {
+			t.Errorf("Blame() before the first commit = %v, want ErrNotCommitted", err)
+		}
+		if err := b.Commit("initial", "a.md", "b.md"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Uncommitted edits to the frontmatter and content shift the lines,
+		// and ending the last line of b.md changes it
+		writeFile(t, dir, "a.md", "---\ntitle: A\ntags: [x]\n---\n\none\n2\nthree\nfour\n")
+		writeFile(t, dir, "b.md", "x\ny\nz\n")
+		writeFile(t, dir, "new.md", "new\n")
+		gitCmd(t, dir, "add", "new.md")
+
+		if _, err := b.Blame("other.md"); !errors.Is(err, ErrNotCommitted) {
+			t.Errorf("Blame() of an untracked file = %v, want ErrNotCommitted", err)
+		}
+
+		var out strings.Builder
+		for _, name := range []string{"a.md", "b.md", "new.md"} {
+			lines, err := b.Blame(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, l := range lines {
+				// Hashes differ with commit times, so compare summaries
+				fmt.Fprintf(&out, "%s %d %s %q %s\n", name, l.Line, l.Author, l.Summary, l.Text)
+			}
+		}
+		return out.String()
+	})
+
+	expectSame(t, "blame", results)
+	if got := strings.Count(results[BackendGo], "Not Committed Yet"); got != 6 {
+		t.Errorf("blame has %d uncommitted lines, want 6:\n%s", got, results[BackendGo])
+	}
+}
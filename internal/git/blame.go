@@ -0,0 +1,148 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameLine describes the commit that last changed a line of a file
+type BlameLine struct {
+	Line    int       `json:"line"`
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Summary string    `json:"summary"`
+	Text    string    `json:"text"`
+}
+
+// BlameHunk is a run of consecutive lines last changed by the same commit
+type BlameHunk struct {
+	StartLine int       `json:"start_line"`
+	EndLine   int       `json:"end_line"`
+	Hash      string    `json:"hash"`
+	Author    string    `json:"author"`
+	Email     string    `json:"email"`
+	Date      time.Time `json:"date"`
+	Summary   string    `json:"summary"`
+}
+
+// uncommittedHash is the all-zero hash git blame uses for local changes
+const uncommittedHash = "0000000000000000000000000000000000000000"
+
+// notCommittedAuthor is the author git blame gives local changes
+const notCommittedAuthor = "Not Committed Yet"
+
+// ErrNotCommitted is returned when blaming a file that has never been
+// committed
+var ErrNotCommitted = errors.New("not committed yet")
+
+// Uncommitted reports whether the hunk holds changes that are not committed
+func (h BlameHunk) Uncommitted() bool {
+	return h.Hash == uncommittedHash
+}
+
+// GroupBlame merges consecutive lines from the same commit into hunks
+func GroupBlame(lines []BlameLine) []BlameHunk {
+	var hunks []BlameHunk
+
+	for _, line := range lines {
+		if n := len(hunks); n > 0 && hunks[n-1].Hash == line.Hash && hunks[n-1].EndLine == line.Line-1 {
+			hunks[n-1].EndLine = line.Line
+			continue
+		}
+
+		hunks = append(hunks, BlameHunk{
+			StartLine: line.Line,
+			EndLine:   line.Line,
+			Hash:      line.Hash,
+			Author:    line.Author,
+			Email:     line.Email,
+			Date:      line.Date,
+			Summary:   line.Summary,
+		})
+	}
+
+	return hunks
+}
+
+// parsePorcelainBlame parses the output of git blame --porcelain. Commit
+// details are only printed the first time a commit appears, so they are
+// remembered by hash.
+func parsePorcelainBlame(output string) ([]BlameLine, error) {
+	type commitInfo struct {
+		author, email, summary string
+		date                   time.Time
+	}
+
+	commits := make(map[string]*commitInfo)
+	var lines []BlameLine
+	var current *BlameLine
+	var info *commitInfo
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		// Content lines are prefixed by a tab and end the entry
+		if strings.HasPrefix(text, "\t") {
+			if current == nil {
+				return nil, fmt.Errorf("unexpected content line in blame output")
+			}
+			current.Text = text[1:]
+			current.Author = info.author
+			current.Email = info.email
+			current.Date = info.date
+			current.Summary = info.summary
+			lines = append(lines, *current)
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			// Header: <hash> <orig-line> <final-line> [<num-lines>]
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("invalid blame header: %q", text)
+			}
+			lineNo, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid blame header: %q", text)
+			}
+
+			hash := fields[0]
+			if commits[hash] == nil {
+				commits[hash] = &commitInfo{}
+			}
+			info = commits[hash]
+			current = &BlameLine{Line: lineNo, Hash: hash}
+			continue
+		}
+
+		key, value, _ := strings.Cut(text, " ")
+		switch key {
+		case "author":
+			info.author = value
+		case "author-mail":
+			info.email = strings.Trim(value, "<>")
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				info.date = time.Unix(sec, 0)
+			}
+		case "summary":
+			info.summary = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read blame output: %w", err)
+	}
+
+	return lines, nil
+}
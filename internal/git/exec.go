@@ -0,0 +1,237 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ExecBackend runs the git binary found in PATH
+type ExecBackend struct {
+	Dir string
+}
+
+// NewExecBackend returns a backend that shells out to git in dir
+func NewExecBackend(dir string) *ExecBackend {
+	return &ExecBackend{Dir: dir}
+}
+
+// run executes git with args in the repository directory
+func (b *ExecBackend) run(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = b.Dir
+	return cmd.CombinedOutput()
+}
+
+func (b *ExecBackend) Init() error {
+	output, err := b.run("init")
+	if err != nil {
+		return fmt.Errorf("could not initialize git repository: %w\n%s", err, output)
+	}
+
+	return nil
+}
+
+func (b *ExecBackend) Clone(url string) error {
+	cmd := exec.Command("git", "clone", url, b.Dir)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("could not clone repository: %w\n%s", err, output)
+	}
+
+	return nil
+}
+
+func (b *ExecBackend) Status() (string, error) {
+	output, err := b.run("status", "--porcelain")
+	if err != nil {
+		return "", fmt.Errorf("could not get git status: %w\n%s", err, output)
+	}
+
+	return string(output), nil
+}
+
+func (b *ExecBackend) StatusShort() (string, error) {
+	output, err := b.run("status", "--short", "--branch")
+	if err != nil {
+		return "", fmt.Errorf("could not get git status: %w\n%s", err, output)
+	}
+
+	return string(output), nil
+}
+
+func (b *ExecBackend) Commit(message string, paths ...string) error {
+	// Stage changes first
+	addArgs := []string{"add", "--all", "--"}
+	if len(paths) == 0 {
+		addArgs = append(addArgs, ".")
+	} else {
+		addArgs = append(addArgs, paths...)
+	}
+
+	if output, err := b.run(addArgs...); err != nil {
+		return fmt.Errorf("could not stage changes: %w\n%s", err, output)
+	}
+
+	commitArgs := []string{"commit", "-m", message}
+	if len(paths) > 0 {
+		commitArgs = append(commitArgs, "--")
+		commitArgs = append(commitArgs, paths...)
+	}
+
+	output, err := b.run(commitArgs...)
+	if err != nil {
+		// Check if there's nothing to commit
+		if strings.Contains(string(output), "nothing to commit") ||
+			strings.Contains(string(output), "no changes added to commit") {
+			return fmt.Errorf("nothing to commit, working tree clean")
+		}
+		return fmt.Errorf("could not create commit: %w\n%s", err, output)
+	}
+
+	return nil
+}
+
+func (b *ExecBackend) Log(maxCount int) (string, error) {
+	args := []string{"log", "--oneline", "--decorate", "--color=always"}
+	if maxCount > 0 {
+		args = append(args, fmt.Sprintf("-n%d", maxCount))
+	}
+
+	output, err := b.run(args...)
+	if err != nil {
+		// A repository without commits has no log
+		if strings.Contains(string(output), "does not have any commits") {
+			return "", nil
+		}
+		return "", fmt.Errorf("could not get git log: %w\n%s", err, output)
+	}
+
+	return string(output), nil
+}
+
+func (b *ExecBackend) Pull() error {
+	output, err := b.run("pull")
+	if err != nil {
+		return fmt.Errorf("could not pull from remote: %w\n%s", err, output)
+	}
+
+	return nil
+}
+
+func (b *ExecBackend) Push() error {
+	output, err := b.run("push")
+	if err != nil {
+		return fmt.Errorf("could not push to remote: %w\n%s", err, output)
+	}
+
+	return nil
+}
+
+func (b *ExecBackend) Diff(paths ...string) (string, error) {
+	args := []string{"diff", "--no-color"}
+	if len(paths) > 0 {
+		args = append(args, "--")
+		args = append(args, paths...)
+	}
+
+	output, err := b.run(args...)
+	if err != nil {
+		return "", fmt.Errorf("could not get git diff: %w\n%s", err, output)
+	}
+
+	return string(output), nil
+}
+
+func (b *ExecBackend) StagedFiles() ([]string, error) {
+	output, err := b.run("diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("could not list staged files: %w\n%s", err, output)
+	}
+
+	var files []string
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+
+	return files, nil
+}
+
+func (b *ExecBackend) StagedContent(path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", ":"+path)
+	cmd.Dir = b.Dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read staged %s: %w", path, err)
+	}
+
+	return output, nil
+}
+
+func (b *ExecBackend) Blame(path string) ([]BlameLine, error) {
+	cmd := exec.Command("git", "blame", "--porcelain", "--", path)
+	cmd.Dir = b.Dir
+	// Untranslated, to recognize files that are not committed
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr := string(exitErr.Stderr)
+			if strings.Contains(stderr, "no such ref: HEAD") || strings.Contains(stderr, "no such path") {
+				return nil, fmt.Errorf("%s: %w", path, ErrNotCommitted)
+			}
+			return nil, fmt.Errorf("could not get git blame: %w\n%s", err, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("could not get git blame: %w", err)
+	}
+
+	return parsePorcelainBlame(string(output))
+}
+
+func (b *ExecBackend) HasRemote() (bool, error) {
+	output, err := b.run("remote", "-v")
+	if err != nil {
+		return false, fmt.Errorf("could not check git remotes: %w", err)
+	}
+
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
+
+func (b *ExecBackend) Remotes() ([]Remote, error) {
+	output, err := b.run("remote")
+	if err != nil {
+		return nil, fmt.Errorf("could not list git remotes: %w\n%s", err, output)
+	}
+
+	var remotes []Remote
+	for _, name := range strings.Fields(string(output)) {
+		url, err := b.run("remote", "get-url", name)
+		if err != nil {
+			return nil, fmt.Errorf("could not get url of remote %s: %w\n%s", name, err, url)
+		}
+		remotes = append(remotes, Remote{Name: name, URL: strings.TrimSpace(string(url))})
+	}
+
+	return remotes, nil
+}
+
+func (b *ExecBackend) AddRemote(name, url string) error {
+	output, err := b.run("remote", "add", name, url)
+	if err != nil {
+		return fmt.Errorf("could not add remote: %w\n%s", err, output)
+	}
+
+	return nil
+}
+
+func (b *ExecBackend) RemoveRemote(name string) error {
+	output, err := b.run("remote", "remove", name)
+	if err != nil {
+		return fmt.Errorf("could not remove remote: %w\n%s", err, output)
+	}
+
+	return nil
+}
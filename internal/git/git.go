@@ -3,7 +3,6 @@ package git
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -18,139 +17,141 @@ func IsGitRepo() bool {
 	return err == nil && info.IsDir()
 }
 
+// repo returns the configured backend, failing if the notes directory
+// is not a git repository
+func repo() (Backend, error) {
+	if !IsGitRepo() {
+		return nil, fmt.Errorf("not a git repository")
+	}
+
+	return Default()
+}
+
 // Init initializes a git repository in the notes directory
 func Init() error {
-	cfg := config.Get()
-
 	if IsGitRepo() {
 		return fmt.Errorf("git repository already exists")
 	}
 
-	cmd := exec.Command("git", "init")
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	b, err := Default()
 	if err != nil {
-		return fmt.Errorf("could not initialize git repository: %w\n%s", err, output)
+		return err
 	}
 
-	return nil
+	return b.Init()
 }
 
 // Status returns the git status of the notes directory
 func Status() (string, error) {
-	cfg := config.Get()
-
 	if !IsGitRepo() {
 		return "", fmt.Errorf("not a git repository (use 'noti git init' to initialize)")
 	}
 
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	b, err := Default()
 	if err != nil {
-		return "", fmt.Errorf("could not get git status: %w\n%s", err, output)
+		return "", err
 	}
 
-	return string(output), nil
+	return b.Status()
 }
 
 // StatusShort returns a short git status summary
 func StatusShort() (string, error) {
-	cfg := config.Get()
-
 	if !IsGitRepo() {
 		return "not a git repository", nil
 	}
 
-	cmd := exec.Command("git", "status", "--short", "--branch")
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	b, err := Default()
 	if err != nil {
-		return "", fmt.Errorf("could not get git status: %w\n%s", err, output)
+		return "", err
 	}
 
-	return strings.TrimSpace(string(output)), nil
-}
-
-// Add stages all changes in the notes directory
-func Add() error {
-	cfg := config.Get()
-
-	if !IsGitRepo() {
-		return fmt.Errorf("not a git repository")
+	status, err := b.StatusShort()
+	if err != nil {
+		return "", err
 	}
 
-	cmd := exec.Command("git", "add", ".")
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	return strings.TrimSpace(status), nil
+}
+
+// Commit stages and commits the given paths, or all changes if none are given
+func Commit(message string, paths ...string) error {
+	b, err := repo()
 	if err != nil {
-		return fmt.Errorf("could not stage changes: %w\n%s", err, output)
+		return err
 	}
 
-	return nil
+	return b.Commit(message, paths...)
 }
 
-// Commit creates a git commit with the given message
-func Commit(message string) error {
-	cfg := config.Get()
-
-	if !IsGitRepo() {
-		return fmt.Errorf("not a git repository")
+// Push pushes commits to the remote repository
+func Push() error {
+	b, err := repo()
+	if err != nil {
+		return err
 	}
 
-	// Stage all changes first
-	if err := Add(); err != nil {
+	return b.Push()
+}
+
+// Pull pulls changes from the remote repository
+func Pull() error {
+	b, err := repo()
+	if err != nil {
 		return err
 	}
 
-	cmd := exec.Command("git", "commit", "-m", message)
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	return b.Pull()
+}
+
+// Diff returns the unstaged changes in the notes directory
+func Diff(paths ...string) (string, error) {
+	b, err := repo()
 	if err != nil {
-		// Check if there's nothing to commit
-		if strings.Contains(string(output), "nothing to commit") {
-			return fmt.Errorf("nothing to commit, working tree clean")
-		}
-		return fmt.Errorf("could not create commit: %w\n%s", err, output)
+		return "", err
 	}
 
-	return nil
+	return b.Diff(paths...)
 }
 
-// Push pushes commits to the remote repository
-func Push() error {
-	cfg := config.Get()
-
-	if !IsGitRepo() {
-		return fmt.Errorf("not a git repository")
+// StagedFiles lists files staged for the next commit, relative to the
+// notes directory
+func StagedFiles() ([]string, error) {
+	b, err := repo()
+	if err != nil {
+		return nil, err
 	}
 
-	cmd := exec.Command("git", "push")
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	return b.StagedFiles()
+}
+
+// StagedContent returns the staged content of a file in the notes directory
+func StagedContent(path string) ([]byte, error) {
+	b, err := repo()
 	if err != nil {
-		return fmt.Errorf("could not push to remote: %w\n%s", err, output)
+		return nil, err
 	}
 
-	return nil
+	return b.StagedContent(path)
 }
 
-// Pull pulls changes from the remote repository
-func Pull() error {
-	cfg := config.Get()
-
+// HooksDir returns the hooks directory of the notes repository
+func HooksDir() (string, error) {
 	if !IsGitRepo() {
-		return fmt.Errorf("not a git repository")
+		return "", fmt.Errorf("not a git repository (use 'noti git init' to initialize)")
 	}
 
-	cmd := exec.Command("git", "pull")
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	return filepath.Join(config.Get().NotesDir, ".git", "hooks"), nil
+}
+
+// Blame returns line authorship for a file in the notes directory
+func Blame(path string) ([]BlameLine, error) {
+	b, err := repo()
 	if err != nil {
-		return fmt.Errorf("could not pull from remote: %w\n%s", err, output)
+		return nil, err
 	}
 
-	return nil
+	return b.Blame(path)
 }
 
 // Sync performs a full sync: add, commit, pull, and push
@@ -195,41 +196,64 @@ func Sync(message string) error {
 
 // Log returns the git log
 func Log(maxCount int) (string, error) {
-	cfg := config.Get()
+	b, err := repo()
+	if err != nil {
+		return "", err
+	}
+
+	return b.Log(maxCount)
+}
 
+// HasRemote checks if a git remote is configured
+func HasRemote() (bool, error) {
 	if !IsGitRepo() {
-		return "", fmt.Errorf("not a git repository")
+		return false, nil
 	}
 
-	args := []string{"log", "--oneline", "--decorate", "--color=always"}
-	if maxCount > 0 {
-		args = append(args, fmt.Sprintf("-n%d", maxCount))
+	b, err := Default()
+	if err != nil {
+		return false, err
 	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	return b.HasRemote()
+}
+
+// Remotes lists the remotes of the notes repository
+func Remotes() ([]Remote, error) {
+	b, err := repo()
 	if err != nil {
-		return "", fmt.Errorf("could not get git log: %w\n%s", err, output)
+		return nil, err
 	}
 
-	return string(output), nil
+	return b.Remotes()
 }
 
-// HasRemote checks if a git remote is configured
-func HasRemote() (bool, error) {
-	cfg := config.Get()
+// AddRemote adds a remote to the notes repository
+func AddRemote(name, url string) error {
+	b, err := repo()
+	if err != nil {
+		return err
+	}
 
-	if !IsGitRepo() {
-		return false, nil
+	return b.AddRemote(name, url)
+}
+
+// RemoveRemote removes a remote from the notes repository
+func RemoveRemote(name string) error {
+	b, err := repo()
+	if err != nil {
+		return err
 	}
 
-	cmd := exec.Command("git", "remote", "-v")
-	cmd.Dir = cfg.NotesDir
-	output, err := cmd.CombinedOutput()
+	return b.RemoveRemote(name)
+}
+
+// Clone clones the repository at url into dir using the configured backend
+func Clone(url, dir string) error {
+	b, err := New(config.Get().GitBackend, dir)
 	if err != nil {
-		return false, fmt.Errorf("could not check git remotes: %w", err)
+		return err
 	}
 
-	return len(strings.TrimSpace(string(output))) > 0, nil
+	return b.Clone(url)
 }
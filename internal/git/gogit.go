@@ -0,0 +1,872 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devjasha/noti-vim/internal/diff"
+	"github.com/go-git/go-billy/v5/osfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// GoBackend implements git operations in pure Go, without a git binary.
+// Pulls only support fast-forward merges.
+type GoBackend struct {
+	Dir string
+}
+
+// localLoader serves local repositories in-process so file remotes work
+// without git-upload-pack. Both bare and non-bare repositories are accepted.
+type localLoader struct{}
+
+func (localLoader) Load(ep *transport.Endpoint) (storer.Storer, error) {
+	for _, dir := range []string{ep.Path, filepath.Join(ep.Path, ".git")} {
+		if _, err := os.Stat(filepath.Join(dir, "config")); err == nil {
+			return filesystem.NewStorage(osfs.New(dir), cache.NewObjectLRUDefault()), nil
+		}
+	}
+
+	return nil, transport.ErrRepositoryNotFound
+}
+
+var installFileTransport sync.Once
+
+// useLocalTransport replaces go-git's file transport, which runs
+// git-upload-pack, with localLoader. It is only installed once a GoBackend
+// talks to a remote, so programs using the exec backend keep the default.
+func useLocalTransport() {
+	installFileTransport.Do(func() {
+		client.InstallProtocol("file", server.NewServer(localLoader{}))
+	})
+}
+
+// NewGoBackend returns a pure-Go backend for the repository in dir
+func NewGoBackend(dir string) *GoBackend {
+	return &GoBackend{Dir: dir}
+}
+
+func (b *GoBackend) open() (*gogit.Repository, *gogit.Worktree, error) {
+	repo, err := gogit.PlainOpen(b.Dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open git repository: %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open worktree: %w", err)
+	}
+
+	return repo, wt, nil
+}
+
+func (b *GoBackend) Init() error {
+	if _, err := gogit.PlainInit(b.Dir, false); err != nil {
+		return fmt.Errorf("could not initialize git repository: %w", err)
+	}
+
+	return nil
+}
+
+func (b *GoBackend) Clone(url string) error {
+	useLocalTransport()
+	if _, err := gogit.PlainClone(b.Dir, false, &gogit.CloneOptions{URL: url}); err != nil {
+		return fmt.Errorf("could not clone repository: %w", err)
+	}
+
+	return nil
+}
+
+func (b *GoBackend) Status() (string, error) {
+	_, wt, err := b.open()
+	if err != nil {
+		return "", err
+	}
+
+	return b.status(wt)
+}
+
+func (b *GoBackend) status(wt *gogit.Worktree) (string, error) {
+	status, err := wt.Status()
+	if err != nil {
+		return "", fmt.Errorf("could not get git status: %w", err)
+	}
+
+	paths := make([]string, 0, len(status))
+	for path := range status {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var buf strings.Builder
+	for _, path := range paths {
+		st := status[path]
+		fmt.Fprintf(&buf, "%c%c %s\n", st.Staging, st.Worktree, path)
+	}
+
+	return buf.String(), nil
+}
+
+func (b *GoBackend) StatusShort() (string, error) {
+	repo, wt, err := b.open()
+	if err != nil {
+		return "", err
+	}
+
+	branch, err := b.branchLine(repo)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := b.status(wt)
+	if err != nil {
+		return "", err
+	}
+
+	return branch + "\n" + status, nil
+}
+
+// branchLine describes the current branch and how far it is from its
+// upstream, in the format of git status --branch
+func (b *GoBackend) branchLine(repo *gogit.Repository) (string, error) {
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", fmt.Errorf("could not get git status: %w", err)
+	}
+	if head.Type() != plumbing.SymbolicReference {
+		return "## HEAD (no branch)", nil
+	}
+
+	branch := head.Target().Short()
+	local, err := repo.Reference(head.Target(), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "## No commits yet on " + branch, nil
+	} else if err != nil {
+		return "", fmt.Errorf("could not get git status: %w", err)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("could not read git config: %w", err)
+	}
+	bc, ok := cfg.Branches[branch]
+	if !ok || bc.Remote == "" || bc.Merge == "" {
+		return "## " + branch, nil
+	}
+
+	line := fmt.Sprintf("## %s...%s/%s", branch, bc.Remote, bc.Merge.Short())
+	upstream, err := repo.Reference(plumbing.NewRemoteReferenceName(bc.Remote, bc.Merge.Short()), true)
+	if err != nil {
+		return line + " [gone]", nil
+	}
+
+	ahead, behind, err := aheadBehind(repo, local.Hash(), upstream.Hash())
+	if err != nil {
+		return "", fmt.Errorf("could not compare with upstream: %w", err)
+	}
+
+	var counts []string
+	if ahead > 0 {
+		counts = append(counts, fmt.Sprintf("ahead %d", ahead))
+	}
+	if behind > 0 {
+		counts = append(counts, fmt.Sprintf("behind %d", behind))
+	}
+	if len(counts) > 0 {
+		line += " [" + strings.Join(counts, ", ") + "]"
+	}
+
+	return line, nil
+}
+
+// aheadBehind counts the commits only reachable from local, and those only
+// reachable from upstream
+func aheadBehind(repo *gogit.Repository, local, upstream plumbing.Hash) (int, int, error) {
+	if local == upstream {
+		return 0, 0, nil
+	}
+
+	ours, err := ancestors(repo, local)
+	if err != nil {
+		return 0, 0, err
+	}
+	theirs, err := ancestors(repo, upstream)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ahead, behind := 0, 0
+	for h := range ours {
+		if !theirs[h] {
+			ahead++
+		}
+	}
+	for h := range theirs {
+		if !ours[h] {
+			behind++
+		}
+	}
+
+	return ahead, behind, nil
+}
+
+func ancestors(repo *gogit.Repository, from plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	commit, err := repo.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[plumbing.Hash]bool)
+	err = object.NewCommitPreorderIter(commit, nil, nil).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+
+	return seen, err
+}
+
+// Commit stages and commits changes like the exec backend. go-git does
+// not run git hooks, so the pre-commit hook is skipped.
+func (b *GoBackend) Commit(message string, paths ...string) error {
+	repo, wt, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	// Like git commit -- paths, changes staged for other files are left
+	// out of the commit but stay staged
+	restore := func() error { return nil }
+	if len(paths) > 0 {
+		rels := make([]string, 0, len(paths))
+		for _, path := range paths {
+			rel, err := b.rel(path)
+			if err != nil {
+				return err
+			}
+			rels = append(rels, rel)
+		}
+
+		if restore, err = b.holdStaged(repo, rels); err != nil {
+			return err
+		}
+	}
+
+	err = b.commit(repo, wt, message, paths)
+	if restoreErr := restore(); err == nil && restoreErr != nil {
+		err = fmt.Errorf("could not restore staged changes: %w", restoreErr)
+	}
+
+	return err
+}
+
+func (b *GoBackend) commit(repo *gogit.Repository, wt *gogit.Worktree, message string, paths []string) error {
+	var err error
+	if len(paths) == 0 {
+		err = wt.AddWithOptions(&gogit.AddOptions{All: true})
+	} else {
+		for _, path := range paths {
+			if err = b.stage(wt, path); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("could not stage changes: %w", err)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return fmt.Errorf("could not get git status: %w", err)
+	}
+
+	staged := false
+	for _, st := range status {
+		if st.Staging != gogit.Unmodified && st.Staging != gogit.Untracked {
+			staged = true
+			break
+		}
+	}
+	if !staged {
+		return fmt.Errorf("nothing to commit, working tree clean")
+	}
+
+	if _, err := wt.Commit(message, &gogit.CommitOptions{Author: b.signature(repo)}); err != nil {
+		return fmt.Errorf("could not create commit: %w", err)
+	}
+
+	return nil
+}
+
+// holdStaged resets the index entries of files outside paths to HEAD, so
+// only paths are committed. The returned function puts the held entries
+// back into the index.
+func (b *GoBackend) holdStaged(repo *gogit.Repository, paths []string) (func() error, error) {
+	inPaths := func(name string) bool {
+		for _, p := range paths {
+			if name == p || strings.HasPrefix(name, strings.TrimSuffix(p, "/")+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	head := make(map[string]*object.File)
+	if ref, err := repo.Head(); err == nil {
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("could not read HEAD: %w", err)
+		}
+		files, err := commit.Files()
+		if err != nil {
+			return nil, fmt.Errorf("could not read HEAD: %w", err)
+		}
+		err = files.ForEach(func(f *object.File) error {
+			head[f.Name] = f
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not read HEAD: %w", err)
+		}
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, fmt.Errorf("could not read HEAD: %w", err)
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("could not read index: %w", err)
+	}
+
+	// held are the staged entries to put back; deleted are the staged
+	// deletions, whose HEAD entries are put back meanwhile
+	var held []index.Entry
+	var deleted []string
+	var entries []*index.Entry
+	for _, e := range idx.Entries {
+		if inPaths(e.Name) {
+			entries = append(entries, e)
+			continue
+		}
+
+		f, ok := head[e.Name]
+		if ok && f.Hash == e.Hash && f.Mode == e.Mode {
+			entries = append(entries, e)
+			continue
+		}
+
+		held = append(held, *e)
+		if ok {
+			reset := *e
+			reset.Hash, reset.Mode = f.Hash, f.Mode
+			entries = append(entries, &reset)
+		}
+	}
+	for name, f := range head {
+		if inPaths(name) {
+			continue
+		}
+		if _, err := idx.Entry(name); err == nil {
+			continue
+		}
+		deleted = append(deleted, name)
+		entries = append(entries, &index.Entry{Name: name, Hash: f.Hash, Mode: f.Mode})
+	}
+
+	if len(held) == 0 && len(deleted) == 0 {
+		return func() error { return nil }, nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	idx.Entries = entries
+	if err := repo.Storer.SetIndex(idx); err != nil {
+		return nil, fmt.Errorf("could not write index: %w", err)
+	}
+
+	return func() error {
+		idx, err := repo.Storer.Index()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range deleted {
+			if _, err := idx.Remove(name); err != nil {
+				return err
+			}
+		}
+		for i := range held {
+			e := held[i]
+			if _, err := idx.Remove(e.Name); err != nil && !errors.Is(err, index.ErrEntryNotFound) {
+				return err
+			}
+			idx.Entries = append(idx.Entries, &e)
+		}
+		sort.Slice(idx.Entries, func(i, j int) bool { return idx.Entries[i].Name < idx.Entries[j].Name })
+
+		return repo.Storer.SetIndex(idx)
+	}, nil
+}
+
+// signature returns the configured git author, falling back to the
+// current user so commits work on machines without any git config
+func (b *GoBackend) signature(repo *gogit.Repository) *object.Signature {
+	if cfg, err := repo.ConfigScoped(config.SystemScope); err == nil && cfg.User.Name != "" && cfg.User.Email != "" {
+		return &object.Signature{Name: cfg.User.Name, Email: cfg.User.Email, When: time.Now()}
+	}
+
+	name := "noti"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+
+	return &object.Signature{Name: name, Email: name + "@" + host, When: time.Now()}
+}
+
+// stage adds a path to the index, or removes it if it no longer exists
+func (b *GoBackend) stage(wt *gogit.Worktree, path string) error {
+	rel, err := b.rel(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(b.Dir, rel)); os.IsNotExist(err) {
+		_, err = wt.Remove(rel)
+		return err
+	}
+
+	_, err = wt.Add(rel)
+	return err
+}
+
+// rel returns path relative to the repository root
+func (b *GoBackend) rel(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path), nil
+	}
+
+	rel, err := filepath.Rel(b.Dir, path)
+	if err != nil {
+		return "", fmt.Errorf("could not get relative path: %w", err)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+func (b *GoBackend) Log(maxCount int) (string, error) {
+	repo, _, err := b.open()
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("could not get git log: %w", err)
+	}
+
+	iter, err := repo.Log(&gogit.LogOptions{From: head.Hash()})
+	if err != nil {
+		return "", fmt.Errorf("could not get git log: %w", err)
+	}
+	defer iter.Close()
+
+	var buf strings.Builder
+	count := 0
+	err = iter.ForEach(func(c *object.Commit) error {
+		if maxCount > 0 && count >= maxCount {
+			return io.EOF
+		}
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		fmt.Fprintf(&buf, "%s %s\n", c.Hash.String()[:7], subject)
+		count++
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("could not get git log: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func (b *GoBackend) Pull() error {
+	useLocalTransport()
+	_, wt, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	err = wt.Pull(&gogit.PullOptions{RemoteName: "origin"})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("could not pull from remote: %w", err)
+	}
+
+	return nil
+}
+
+func (b *GoBackend) Push() error {
+	useLocalTransport()
+	repo, _, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	err = repo.Push(&gogit.PushOptions{RemoteName: "origin"})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("could not push to remote: %w", err)
+	}
+
+	return nil
+}
+
+func (b *GoBackend) Diff(paths ...string) (string, error) {
+	repo, wt, err := b.open()
+	if err != nil {
+		return "", err
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return "", fmt.Errorf("could not get git status: %w", err)
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return "", fmt.Errorf("could not read index: %w", err)
+	}
+
+	filter := make(map[string]bool)
+	for _, path := range paths {
+		rel, err := b.rel(path)
+		if err != nil {
+			return "", err
+		}
+		filter[rel] = true
+	}
+
+	var changed []string
+	for path, st := range status {
+		// Like git diff, only show tracked files that differ from the index
+		if st.Worktree == gogit.Unmodified || st.Worktree == gogit.Untracked {
+			continue
+		}
+		if len(filter) > 0 && !filter[path] {
+			continue
+		}
+		changed = append(changed, path)
+	}
+	sort.Strings(changed)
+
+	var buf strings.Builder
+	for _, path := range changed {
+		var before string
+		if entry, err := idx.Entry(path); err == nil {
+			blob, err := repo.BlobObject(entry.Hash)
+			if err != nil {
+				return "", fmt.Errorf("could not read %s from index: %w", path, err)
+			}
+			if before, err = readBlob(blob); err != nil {
+				return "", err
+			}
+		}
+
+		var after string
+		if data, err := os.ReadFile(filepath.Join(b.Dir, path)); err == nil {
+			after = string(data)
+		}
+
+		fromName, toName := "a/"+path, "b/"+path
+		if status[path].Worktree == gogit.Deleted {
+			toName = "/dev/null"
+		}
+
+		fmt.Fprintf(&buf, "diff --git a/%s b/%s\n", path, path)
+		buf.WriteString(diff.Unified(fromName, toName, before, after, 3))
+	}
+
+	return buf.String(), nil
+}
+
+func (b *GoBackend) StagedFiles() ([]string, error) {
+	_, wt, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("could not get git status: %w", err)
+	}
+
+	var files []string
+	for path, st := range status {
+		switch st.Staging {
+		case gogit.Added, gogit.Copied, gogit.Modified, gogit.Renamed:
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (b *GoBackend) StagedContent(path string) ([]byte, error) {
+	repo, _, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("could not read index: %w", err)
+	}
+
+	entry, err := idx.Entry(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read staged %s: %w", path, err)
+	}
+
+	blob, err := repo.BlobObject(entry.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("could not read staged %s: %w", path, err)
+	}
+
+	content, err := readBlob(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(content), nil
+}
+
+// Blame attributes the lines of path in the working tree, like git blame:
+// lines that match HEAD are blamed on their commits, and lines changed
+// since are uncommitted.
+func (b *GoBackend) Blame(path string) ([]BlameLine, error) {
+	repo, _, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := b.rel(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(b.Dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, fmt.Errorf("could not get git blame: %w", err)
+	}
+	working := splitBlameLines(string(data))
+
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, fmt.Errorf("%s: %w", rel, ErrNotCommitted)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get git blame: %w", err)
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("could not get git blame: %w", err)
+	}
+
+	file, err := commit.File(rel)
+	if errors.Is(err, object.ErrFileNotFound) {
+		// A staged new file is all uncommitted, as with git blame
+		if idx, err := repo.Storer.Index(); err == nil {
+			if _, err := idx.Entry(rel); err == nil {
+				return uncommittedLines(rel, working), nil
+			}
+		}
+		return nil, fmt.Errorf("%s: %w", rel, ErrNotCommitted)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get git blame: %w", err)
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("could not get git blame: %w", err)
+	}
+	committed := splitBlameLines(contents)
+
+	result, err := gogit.Blame(commit, rel)
+	if err != nil {
+		return nil, fmt.Errorf("could not get git blame: %w", err)
+	}
+	if len(result.Lines) != len(committed) {
+		return nil, fmt.Errorf("could not get git blame: %d lines blamed of %d in %s", len(result.Lines), len(committed), rel)
+	}
+
+	summaries := make(map[plumbing.Hash]string)
+	lines := make([]BlameLine, 0, len(working))
+	i := 0
+	for _, op := range diff.Lines(committed, working) {
+		switch op.Kind {
+		case diff.Delete:
+			i++
+			continue
+		case diff.Insert:
+			lines = append(lines, uncommittedLine(rel, len(lines)+1, strings.TrimSuffix(op.Text, "\n")))
+			continue
+		}
+
+		l := result.Lines[i]
+		i++
+		summary, ok := summaries[l.Hash]
+		if !ok {
+			if c, err := repo.CommitObject(l.Hash); err == nil {
+				summary, _, _ = strings.Cut(c.Message, "\n")
+			}
+			summaries[l.Hash] = summary
+		}
+
+		lines = append(lines, BlameLine{
+			Line:    len(lines) + 1,
+			Hash:    l.Hash.String(),
+			Author:  l.AuthorName,
+			Email:   l.Author,
+			Date:    l.Date,
+			Summary: summary,
+			Text:    l.Text,
+		})
+	}
+
+	return lines, nil
+}
+
+// splitBlameLines splits file content into lines with their line endings,
+// so a last line without one differs from the same line with one, as it
+// does for git blame
+func splitBlameLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func uncommittedLines(path string, texts []string) []BlameLine {
+	lines := make([]BlameLine, len(texts))
+	for i, text := range texts {
+		lines[i] = uncommittedLine(path, i+1, strings.TrimSuffix(text, "\n"))
+	}
+	return lines
+}
+
+// uncommittedLine describes a local change the way git blame does
+func uncommittedLine(path string, n int, text string) BlameLine {
+	return BlameLine{
+		Line:    n,
+		Hash:    uncommittedHash,
+		Author:  notCommittedAuthor,
+		Email:   "not.committed.yet",
+		Date:    time.Now(),
+		Summary: fmt.Sprintf("Version of %s from %s", path, path),
+		Text:    text,
+	}
+}
+
+func (b *GoBackend) HasRemote() (bool, error) {
+	repo, _, err := b.open()
+	if err != nil {
+		return false, err
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return false, fmt.Errorf("could not check git remotes: %w", err)
+	}
+
+	return len(remotes) > 0, nil
+}
+
+func (b *GoBackend) Remotes() ([]Remote, error) {
+	repo, _, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("could not list git remotes: %w", err)
+	}
+
+	var remotes []Remote
+	for _, r := range list {
+		cfg := r.Config()
+		url := ""
+		if len(cfg.URLs) > 0 {
+			url = cfg.URLs[0]
+		}
+		remotes = append(remotes, Remote{Name: cfg.Name, URL: url})
+	}
+
+	sort.Slice(remotes, func(i, j int) bool {
+		return remotes[i].Name < remotes[j].Name
+	})
+
+	return remotes, nil
+}
+
+func (b *GoBackend) AddRemote(name, url string) error {
+	repo, _, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}}); err != nil {
+		return fmt.Errorf("could not add remote: %w", err)
+	}
+
+	return nil
+}
+
+func (b *GoBackend) RemoveRemote(name string) error {
+	repo, _, err := b.open()
+	if err != nil {
+		return err
+	}
+
+	if err := repo.DeleteRemote(name); err != nil {
+		return fmt.Errorf("could not remove remote: %w", err)
+	}
+
+	return nil
+}
+
+func readBlob(blob *object.Blob) (string, error) {
+	r, err := blob.Reader()
+	if err != nil {
+		return "", fmt.Errorf("could not read blob: %w", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("could not read blob: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,198 @@
+package links
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+// Link kinds
+const (
+	KindWiki     = "wiki"
+	KindMarkdown = "markdown"
+)
+
+// Link is a reference from a note to another note
+type Link struct {
+	// Target is the referenced note, without heading anchor or display text
+	Target string `json:"target"`
+	// Raw is the link as written
+	Raw  string `json:"raw"`
+	Kind string `json:"kind"`
+	// Line and Col are 1-based positions within the note content
+	Line int `json:"line"`
+	Col  int `json:"col"`
+}
+
+var (
+	wikiLinkPattern     = regexp.MustCompile(`\[\[([^\[\]]+)\]\]`)
+	markdownLinkPattern = regexp.MustCompile(`\[[^\[\]]*\]\(([^()\s]+)\)`)
+	inlineCodePattern   = regexp.MustCompile("`[^`]*`")
+)
+
+// Extract returns the links to other notes in content. Links inside fenced
+// code blocks and inline code are ignored, as are external URLs.
+func Extract(content string) []Link {
+	var result []Link
+	inFence := false
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		// Blank out inline code so offsets stay the same
+		masked := inlineCodePattern.ReplaceAllStringFunc(line, func(s string) string {
+			return strings.Repeat(" ", len(s))
+		})
+
+		for _, m := range wikiLinkPattern.FindAllStringSubmatchIndex(masked, -1) {
+			target := line[m[2]:m[3]]
+			if i := strings.Index(target, "|"); i >= 0 {
+				target = target[:i]
+			}
+			if i := strings.Index(target, "#"); i >= 0 {
+				target = target[:i]
+			}
+			target = strings.TrimSpace(target)
+			if target == "" {
+				continue
+			}
+
+			result = append(result, Link{
+				Target: target,
+				Raw:    line[m[0]:m[1]],
+				Kind:   KindWiki,
+				Line:   i + 1,
+				Col:    m[0] + 1,
+			})
+		}
+
+		for _, m := range markdownLinkPattern.FindAllStringSubmatchIndex(masked, -1) {
+			target, ok := markdownTarget(line[m[2]:m[3]])
+			if !ok {
+				continue
+			}
+
+			result = append(result, Link{
+				Target: target,
+				Raw:    line[m[0]:m[1]],
+				Kind:   KindMarkdown,
+				Line:   i + 1,
+				Col:    m[0] + 1,
+			})
+		}
+	}
+
+	return result
+}
+
+// markdownTarget returns the note path of a markdown link destination, or
+// false if the link points outside the vault or to a non-note file
+func markdownTarget(dest string) (string, bool) {
+	if strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") || strings.HasPrefix(dest, "#") {
+		return "", false
+	}
+
+	if i := strings.Index(dest, "#"); i >= 0 {
+		dest = dest[:i]
+	}
+
+	if unescaped, err := url.PathUnescape(dest); err == nil {
+		dest = unescaped
+	}
+
+	if !strings.HasSuffix(dest, ".md") {
+		return "", false
+	}
+
+	return dest, true
+}
+
+// Resolver looks up link targets among a set of notes
+type Resolver struct {
+	bySlug  map[string]*notes.Note
+	byID    map[string]*notes.Note
+	byName  map[string][]*notes.Note
+	byAlias map[string][]*notes.Note
+	byTitle map[string][]*notes.Note
+}
+
+// NewResolver indexes notes for link resolution
+func NewResolver(all []*notes.Note) *Resolver {
+	r := &Resolver{
+		bySlug:  make(map[string]*notes.Note),
+		byID:    make(map[string]*notes.Note),
+		byName:  make(map[string][]*notes.Note),
+		byAlias: make(map[string][]*notes.Note),
+		byTitle: make(map[string][]*notes.Note),
+	}
+
+	for _, note := range all {
+		r.bySlug[strings.ToLower(note.Slug)] = note
+		if note.ID != "" {
+			r.byID[strings.ToLower(note.ID)] = note
+		}
+		name := strings.ToLower(path.Base(note.Slug))
+		r.byName[name] = append(r.byName[name], note)
+		for _, alias := range note.Aliases {
+			if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+				r.byAlias[alias] = append(r.byAlias[alias], note)
+			}
+		}
+		if note.Title != "" {
+			title := strings.ToLower(note.Title)
+			r.byTitle[title] = append(r.byTitle[title], note)
+		}
+	}
+
+	return r
+}
+
+// Resolve returns the note a link points to. Markdown links are resolved
+// relative to the folder of the linking note; wikilinks match a slug, an
+// id, a file name, an alias, or a title (case-insensitive), in that order.
+func (r *Resolver) Resolve(link Link, fromFolder string) (*notes.Note, bool) {
+	if link.Kind == KindMarkdown {
+		target := link.Target
+		if !strings.HasPrefix(target, "/") {
+			target = path.Join(fromFolder, target)
+		}
+		slug := strings.TrimPrefix(strings.TrimSuffix(path.Clean(target), ".md"), "/")
+		note, ok := r.bySlug[strings.ToLower(slug)]
+		return note, ok
+	}
+
+	return r.Lookup(link.Target)
+}
+
+// Lookup finds a note by slug, id, file name, alias, or title
+func (r *Resolver) Lookup(name string) (*notes.Note, bool) {
+	key := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), ".md"))
+
+	if note, ok := r.bySlug[key]; ok {
+		return note, true
+	}
+	if note, ok := r.byID[key]; ok {
+		return note, true
+	}
+	if matches := r.byName[key]; len(matches) > 0 {
+		return matches[0], true
+	}
+	if matches := r.byAlias[key]; len(matches) > 0 {
+		return matches[0], true
+	}
+	if matches := r.byTitle[key]; len(matches) > 0 {
+		return matches[0], true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/devjasha/noti-vim/internal/links"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/vocab"
+)
+
+// Diagnostic is a single problem found in a note
+type Diagnostic struct {
+	// File is the note path relative to the notes directory
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String formats the diagnostic as file:line:col: message
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", d.File, d.Line, d.Col, d.Message, d.Rule)
+}
+
+// Context holds vault-wide information available to rules
+type Context struct {
+	Links *links.Resolver
+	// Vocabulary is the vault's tag vocabulary, if any
+	Vocabulary *vocab.Vocabulary
+}
+
+// Rule checks a single aspect of a note
+type Rule interface {
+	// Name is the identifier used in diagnostics and configuration
+	Name() string
+	// Description explains what the rule checks
+	Description() string
+	// Check returns the problems found in doc
+	Check(doc *notes.Document, ctx *Context) []Diagnostic
+}
+
+// Fixer is implemented by rules that can correct the problems they report
+type Fixer interface {
+	// Fix corrects doc in place and reports whether anything changed
+	Fix(doc *notes.Document) bool
+}
+
+// Enabled returns the rules that are switched on in settings. Rules not
+// mentioned in settings are enabled. Unknown rule names are an error.
+func Enabled(rules []Rule, settings map[string]bool) ([]Rule, error) {
+	known := make(map[string]bool)
+	for _, rule := range rules {
+		known[rule.Name()] = true
+	}
+
+	for name := range settings {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+	}
+
+	var enabled []Rule
+	for _, rule := range rules {
+		if on, ok := settings[rule.Name()]; ok && !on {
+			continue
+		}
+		enabled = append(enabled, rule)
+	}
+
+	return enabled, nil
+}
+
+// Linter runs a set of rules over documents
+type Linter struct {
+	Rules   []Rule
+	Context *Context
+}
+
+// New creates a linter with the given rules
+func New(rules []Rule, ctx *Context) *Linter {
+	return &Linter{Rules: rules, Context: ctx}
+}
+
+// Lint checks all documents and returns the diagnostics sorted by
+// file and position
+func (l *Linter) Lint(docs []*notes.Document) []Diagnostic {
+	var diags []Diagnostic
+
+	for _, doc := range docs {
+		for _, rule := range l.Rules {
+			diags = append(diags, rule.Check(doc, l.Context)...)
+		}
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File < diags[j].File
+		}
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Col < diags[j].Col
+	})
+
+	return diags
+}
+
+// Fix applies every fixable rule to doc and reports whether it changed
+func (l *Linter) Fix(doc *notes.Document) bool {
+	if doc.ParseErr != nil {
+		return false
+	}
+
+	changed := false
+	for _, rule := range l.Rules {
+		if fixer, ok := rule.(Fixer); ok && fixer.Fix(doc) {
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,473 @@
+package lint
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/devjasha/noti-vim/internal/links"
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/internal/vocab"
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+)
+
+// Rules returns every built-in rule
+func Rules() []Rule {
+	return []Rule{
+		frontmatterRule{},
+		missingTitleRule{},
+		emptyTagsRule{},
+		duplicateTagsRule{},
+		zeroCreatedRule{},
+		titleHeadingRule{},
+		trailingWhitespaceRule{},
+		brokenLinksRule{},
+		vocabularyRule{},
+	}
+}
+
+// frontmatterRule requires parseable YAML frontmatter
+type frontmatterRule struct{}
+
+func (frontmatterRule) Name() string { return "frontmatter" }
+
+func (frontmatterRule) Description() string {
+	return "note must start with valid YAML frontmatter"
+}
+
+func (r frontmatterRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if doc.ParseErr != nil {
+		return []Diagnostic{{
+			File:    doc.Path,
+			Line:    1,
+			Col:     1,
+			Rule:    r.Name(),
+			Message: fmt.Sprintf("invalid frontmatter: %v", doc.ParseErr),
+		}}
+	}
+
+	if doc.Frontmatter == nil {
+		return []Diagnostic{{
+			File:    doc.Path,
+			Line:    1,
+			Col:     1,
+			Rule:    r.Name(),
+			Message: "missing frontmatter",
+		}}
+	}
+
+	return nil
+}
+
+// Fix adds frontmatter to a plain markdown file. Invalid frontmatter is
+// left for the user to repair.
+func (frontmatterRule) Fix(doc *notes.Document) bool {
+	if doc.ParseErr != nil || doc.Frontmatter != nil {
+		return false
+	}
+
+	doc.Frontmatter = &frontmatter.Frontmatter{
+		Title:   defaultTitle(doc),
+		Tags:    []string{},
+		Created: createdTime(doc),
+	}
+	return true
+}
+
+// missingTitleRule requires a non-empty title
+type missingTitleRule struct{}
+
+func (missingTitleRule) Name() string { return "missing-title" }
+
+func (missingTitleRule) Description() string {
+	return "frontmatter must have a title"
+}
+
+func (r missingTitleRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if doc.Frontmatter == nil || strings.TrimSpace(doc.Frontmatter.Title) != "" {
+		return nil
+	}
+
+	return []Diagnostic{{
+		File:    doc.Path,
+		Line:    doc.KeyLine("title"),
+		Col:     1,
+		Rule:    r.Name(),
+		Message: "missing title",
+	}}
+}
+
+// Fix uses the first heading, or the file name, as the title
+func (missingTitleRule) Fix(doc *notes.Document) bool {
+	if doc.Frontmatter == nil || strings.TrimSpace(doc.Frontmatter.Title) != "" {
+		return false
+	}
+
+	doc.Frontmatter.Title = defaultTitle(doc)
+	return true
+}
+
+// emptyTagsRule reports blank entries in the tag list
+type emptyTagsRule struct{}
+
+func (emptyTagsRule) Name() string { return "empty-tags" }
+
+func (emptyTagsRule) Description() string {
+	return "tags must not be empty strings"
+}
+
+func (r emptyTagsRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if doc.Frontmatter == nil {
+		return nil
+	}
+
+	for _, tag := range doc.Frontmatter.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return []Diagnostic{{
+				File:    doc.Path,
+				Line:    doc.KeyLine("tags"),
+				Col:     1,
+				Rule:    r.Name(),
+				Message: "empty tag in tag list",
+			}}
+		}
+	}
+
+	return nil
+}
+
+func (emptyTagsRule) Fix(doc *notes.Document) bool {
+	if doc.Frontmatter == nil {
+		return false
+	}
+
+	var tags []string
+	for _, tag := range doc.Frontmatter.Tags {
+		if strings.TrimSpace(tag) != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) == len(doc.Frontmatter.Tags) {
+		return false
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	doc.Frontmatter.Tags = tags
+	return true
+}
+
+// duplicateTagsRule reports tags listed more than once
+type duplicateTagsRule struct{}
+
+func (duplicateTagsRule) Name() string { return "duplicate-tags" }
+
+func (duplicateTagsRule) Description() string {
+	return "each tag must be listed only once"
+}
+
+func (r duplicateTagsRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if doc.Frontmatter == nil {
+		return nil
+	}
+
+	var diags []Diagnostic
+	seen := make(map[string]bool)
+	for _, tag := range doc.Frontmatter.Tags {
+		if seen[tag] {
+			diags = append(diags, Diagnostic{
+				File:    doc.Path,
+				Line:    doc.KeyLine("tags"),
+				Col:     1,
+				Rule:    r.Name(),
+				Message: fmt.Sprintf("duplicate tag %q", tag),
+			})
+		}
+		seen[tag] = true
+	}
+
+	return diags
+}
+
+func (duplicateTagsRule) Fix(doc *notes.Document) bool {
+	if doc.Frontmatter == nil {
+		return false
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range doc.Frontmatter.Tags {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+
+	if len(tags) == len(doc.Frontmatter.Tags) {
+		return false
+	}
+	doc.Frontmatter.Tags = tags
+	return true
+}
+
+// zeroCreatedRule requires a creation date
+type zeroCreatedRule struct{}
+
+func (zeroCreatedRule) Name() string { return "zero-created" }
+
+func (zeroCreatedRule) Description() string {
+	return "frontmatter must have a created date"
+}
+
+func (r zeroCreatedRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if doc.Frontmatter == nil || !doc.Frontmatter.Created.IsZero() {
+		return nil
+	}
+
+	return []Diagnostic{{
+		File:    doc.Path,
+		Line:    doc.KeyLine("created"),
+		Col:     1,
+		Rule:    r.Name(),
+		Message: "missing created date",
+	}}
+}
+
+// Fix uses the file modification time as the creation date
+func (zeroCreatedRule) Fix(doc *notes.Document) bool {
+	if doc.Frontmatter == nil || !doc.Frontmatter.Created.IsZero() {
+		return false
+	}
+
+	doc.Frontmatter.Created = createdTime(doc)
+	return true
+}
+
+// titleHeadingRule requires the first level-one heading to match the title
+type titleHeadingRule struct{}
+
+func (titleHeadingRule) Name() string { return "title-heading" }
+
+func (titleHeadingRule) Description() string {
+	return "the first # heading must match the title"
+}
+
+func (r titleHeadingRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if doc.Frontmatter == nil || doc.Frontmatter.Title == "" {
+		return nil
+	}
+
+	line, heading, ok := firstHeading(doc.Content)
+	if !ok || heading == strings.TrimSpace(doc.Frontmatter.Title) {
+		return nil
+	}
+
+	return []Diagnostic{{
+		File:    doc.Path,
+		Line:    doc.FileLine(line),
+		Col:     1,
+		Rule:    r.Name(),
+		Message: fmt.Sprintf("heading %q does not match title %q", heading, doc.Frontmatter.Title),
+	}}
+}
+
+// Fix rewrites the heading to match the title
+func (titleHeadingRule) Fix(doc *notes.Document) bool {
+	if doc.Frontmatter == nil || doc.Frontmatter.Title == "" {
+		return false
+	}
+
+	line, heading, ok := firstHeading(doc.Content)
+	title := strings.TrimSpace(doc.Frontmatter.Title)
+	if !ok || heading == title {
+		return false
+	}
+
+	lines := strings.Split(doc.Content, "\n")
+	lines[line-1] = "# " + title
+	doc.Content = strings.Join(lines, "\n")
+	return true
+}
+
+// trailingWhitespaceRule reports whitespace at the end of content lines.
+// Exactly two trailing spaces are a markdown line break and are allowed.
+// The \r of a CRLF line ending is not whitespace here, and lines in fenced
+// code blocks are left alone, since their spaces may matter.
+type trailingWhitespaceRule struct{}
+
+func (trailingWhitespaceRule) Name() string { return "trailing-whitespace" }
+
+func (trailingWhitespaceRule) Description() string {
+	return "lines must not end with whitespace (except a two-space line break)"
+}
+
+func (r trailingWhitespaceRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	var diags []Diagnostic
+
+	lines := strings.Split(doc.Content, "\n")
+	fenced := notes.FencedLines(lines)
+	for i, line := range lines {
+		trimmed := trimTrailing(line)
+		if fenced[i] || trimmed == line {
+			continue
+		}
+
+		diags = append(diags, Diagnostic{
+			File:    doc.Path,
+			Line:    doc.FileLine(i + 1),
+			Col:     len(trimmed) + 1,
+			Rule:    r.Name(),
+			Message: "trailing whitespace",
+		})
+	}
+
+	return diags
+}
+
+func (trailingWhitespaceRule) Fix(doc *notes.Document) bool {
+	lines := strings.Split(doc.Content, "\n")
+	fenced := notes.FencedLines(lines)
+	changed := false
+
+	for i, line := range lines {
+		if trimmed := trimTrailing(line); !fenced[i] && trimmed != line {
+			lines[i] = trimmed
+			changed = true
+		}
+	}
+
+	if changed {
+		doc.Content = strings.Join(lines, "\n")
+	}
+	return changed
+}
+
+// trimTrailing removes trailing whitespace unless it is a markdown line
+// break. A CRLF line ending is kept.
+func trimTrailing(line string) string {
+	body, cr := strings.CutSuffix(line, "\r")
+	trimmed := strings.TrimRight(body, " \t")
+	if body[len(trimmed):] == "  " && strings.TrimSpace(trimmed) != "" {
+		return line
+	}
+	if cr {
+		return trimmed + "\r"
+	}
+	return trimmed
+}
+
+// brokenLinksRule reports links to notes that do not exist
+type brokenLinksRule struct{}
+
+func (brokenLinksRule) Name() string { return "broken-links" }
+
+func (brokenLinksRule) Description() string {
+	return "wikilinks and markdown links must point to existing notes"
+}
+
+func (r brokenLinksRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if ctx == nil || ctx.Links == nil {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for _, link := range links.Extract(doc.Content) {
+		if _, ok := ctx.Links.Resolve(link, doc.Folder()); ok {
+			continue
+		}
+
+		diags = append(diags, Diagnostic{
+			File:    doc.Path,
+			Line:    doc.FileLine(link.Line),
+			Col:     link.Col,
+			Rule:    r.Name(),
+			Message: fmt.Sprintf("broken link to %q", link.Target),
+		})
+	}
+
+	return diags
+}
+
+// vocabularyRule reports tags missing from the tag vocabulary and synonyms
+// used in place of the canonical tag
+type vocabularyRule struct{}
+
+func (vocabularyRule) Name() string { return "tag-vocabulary" }
+
+func (vocabularyRule) Description() string {
+	return "tags must be canonical tags from " + vocab.Source + " (if defined)"
+}
+
+func (r vocabularyRule) Check(doc *notes.Document, ctx *Context) []Diagnostic {
+	if ctx == nil || ctx.Vocabulary == nil || ctx.Vocabulary.Empty() || doc.Frontmatter == nil {
+		return nil
+	}
+
+	var diags []Diagnostic
+	for _, tag := range doc.Frontmatter.Tags {
+		canonical, ok := ctx.Vocabulary.Canonical(tag)
+
+		message := ""
+		switch {
+		case !ok:
+			message = fmt.Sprintf("tag %q is not in the vocabulary", tag)
+		case canonical != tag:
+			message = fmt.Sprintf("tag %q should be %q", tag, canonical)
+		default:
+			continue
+		}
+
+		diags = append(diags, Diagnostic{
+			File:    doc.Path,
+			Line:    doc.KeyLine("tags"),
+			Col:     1,
+			Rule:    r.Name(),
+			Message: message,
+		})
+	}
+
+	return diags
+}
+
+// firstHeading returns the content line and text of the first level-one
+// heading outside code blocks
+func firstHeading(content string) (int, string, bool) {
+	inFence := false
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence && strings.HasPrefix(line, "# ") {
+			return i + 1, strings.TrimSpace(line[2:]), true
+		}
+	}
+
+	return 0, "", false
+}
+
+// defaultTitle derives a title from the first heading or the file name
+func defaultTitle(doc *notes.Document) string {
+	if _, heading, ok := firstHeading(doc.Content); ok && heading != "" {
+		return heading
+	}
+
+	name := strings.TrimSuffix(path.Base(doc.Path), ".md")
+	name = strings.ReplaceAll(name, "-", " ")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// createdTime returns the best available creation time for a document
+func createdTime(doc *notes.Document) time.Time {
+	if !doc.ModTime.IsZero() {
+		return doc.ModTime.Truncate(time.Second)
+	}
+	return time.Now().Truncate(time.Second)
+}
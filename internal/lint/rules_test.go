@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+func TestTrailingWhitespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		lines   []int
+		fixed   string
+	}{
+		{
+			name:    "spaces and tabs",
+			content: "one \ntwo\t\nline break  \nthree",
+			lines:   []int{5, 6},
+			fixed:   "one\ntwo\nline break  \nthree",
+		},
+		{
+			name:    "CRLF line endings",
+			content: "one\r\ntwo \r\nline break  \r\n\r\nthree",
+			lines:   []int{6},
+			fixed:   "one\r\ntwo\r\nline break  \r\n\r\nthree",
+		},
+		{
+			name:    "fenced code",
+			content: "text \n```sh\necho \\  \n  indented \n```\n~~~~\n``` \n~~~~\nafter \nend",
+			lines:   []int{5, 13},
+			fixed:   "text\n```sh\necho \\  \n  indented \n```\n~~~~\n``` \n~~~~\nafter\nend",
+		},
+	}
+
+	// Content starts on file line 5, after the frontmatter
+	rule := trailingWhitespaceRule{}
+	for _, tt := range tests {
+		doc := notes.NewDocument("n.md", []byte("---\ntitle: N\n---\n\n"+tt.content))
+
+		var lines []int
+		for _, d := range rule.Check(doc, nil) {
+			lines = append(lines, d.Line)
+		}
+		if fmt.Sprint(lines) != fmt.Sprint(tt.lines) {
+			t.Errorf("%s: Check() reported lines %v, want %v", tt.name, lines, tt.lines)
+		}
+
+		changed := rule.Fix(doc)
+		if changed != (len(tt.lines) > 0) || doc.Content != tt.fixed {
+			t.Errorf("%s: Fix() = %v, content %q, want %q", tt.name, changed, doc.Content, tt.fixed)
+		}
+		if strings.Count(doc.Content, "\r") != strings.Count(tt.content, "\r") {
+			t.Errorf("%s: Fix() changed line endings", tt.name)
+		}
+	}
+}
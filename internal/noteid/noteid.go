@@ -0,0 +1,96 @@
+package noteid
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	// FormatNone disables IDs for new notes
+	FormatNone = ""
+	// FormatTimestamp produces Zettelkasten-style IDs such as 20240301143000
+	FormatTimestamp = "timestamp"
+	// FormatULID produces sortable random IDs such as 01HQ3K4N8M6VZ2X5T7Y9B1C0DE
+	FormatULID = "ulid"
+)
+
+// timestampLayout is the layout of timestamp IDs
+const timestampLayout = "20060102150405"
+
+// crockford is the base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Validate checks that format is a known ID format
+func Validate(format string) error {
+	switch format {
+	case FormatNone, FormatTimestamp, FormatULID:
+		return nil
+	default:
+		return fmt.Errorf("unknown id format %q (use %q or %q)", format, FormatTimestamp, FormatULID)
+	}
+}
+
+// New generates an ID in the given format for a note created at t. taken
+// reports IDs already in use; timestamp IDs move forward a second at a time
+// until a free one is found. FormatNone yields an empty ID.
+func New(format string, t time.Time, taken func(string) bool) (string, error) {
+	if taken == nil {
+		taken = func(string) bool { return false }
+	}
+
+	switch format {
+	case FormatNone:
+		return "", nil
+	case FormatTimestamp:
+		t = t.Truncate(time.Second)
+		for {
+			id := t.Format(timestampLayout)
+			if !taken(id) {
+				return id, nil
+			}
+			t = t.Add(time.Second)
+		}
+	case FormatULID:
+		for {
+			id, err := ulid(t)
+			if err != nil {
+				return "", err
+			}
+			if !taken(id) {
+				return id, nil
+			}
+		}
+	default:
+		return "", Validate(format)
+	}
+}
+
+// ulid encodes a 48-bit millisecond timestamp followed by 80 random bits
+// as 26 Crockford base32 characters
+func ulid(t time.Time) (string, error) {
+	var b [16]byte
+
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", fmt.Errorf("could not generate ULID: %w", err)
+	}
+
+	n := new(big.Int).SetBytes(b[:])
+	base := big.NewInt(32)
+	digit := new(big.Int)
+
+	out := make([]byte, 26)
+	for i := len(out) - 1; i >= 0; i-- {
+		n.DivMod(n, base, digit)
+		out[i] = crockford[digit.Int64()]
+	}
+
+	return string(out), nil
+}
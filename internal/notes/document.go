@@ -0,0 +1,167 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+)
+
+// Document is a note file as written on disk, for commands that check or
+// rewrite notes without going through Note
+type Document struct {
+	// Path is the file path relative to the notes directory, with forward slashes
+	Path string
+	Data []byte
+	// Frontmatter is nil if the file has none or it failed to parse
+	Frontmatter *frontmatter.Frontmatter
+	Content     string
+	// ContentLine is the file line on which Content begins
+	ContentLine int
+	// ParseErr holds the frontmatter parse error, if any
+	ParseErr error
+	// ModTime is the file modification time, zero for staged content
+	ModTime time.Time
+}
+
+// NewDocument parses the raw data of a note file
+func NewDocument(relPath string, data []byte) *Document {
+	doc := &Document{
+		Path:        filepath.ToSlash(relPath),
+		Data:        data,
+		ContentLine: frontmatter.ContentLine(data),
+	}
+
+	fm, content, err := frontmatter.Parse(data)
+	if err != nil {
+		doc.ParseErr = err
+		doc.Content = string(data)
+		doc.ContentLine = 1
+		return doc
+	}
+
+	doc.Frontmatter = fm
+	doc.Content = content
+	return doc
+}
+
+// LoadDocument reads a note file below root
+func LoadDocument(root, relPath string) (*Document, error) {
+	data, err := os.ReadFile(filepath.Join(root, relPath))
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", relPath, err)
+	}
+
+	doc := NewDocument(relPath, data)
+	if info, err := os.Stat(filepath.Join(root, relPath)); err == nil {
+		doc.ModTime = info.ModTime()
+	}
+
+	return doc, nil
+}
+
+// LoadDocuments reads every note below root
+func LoadDocuments(root string) ([]*Document, error) {
+	var docs []*Document
+
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if relPath != "." && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !IsNote(relPath) {
+			return nil
+		}
+
+		doc, err := LoadDocument(root, relPath)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("could not walk notes directory: %w", err)
+	}
+
+	return docs, nil
+}
+
+// Folder returns the folder of the note, as used for relative links
+func (d *Document) Folder() string {
+	folder := path.Dir(d.Path)
+	if folder == "." {
+		return ""
+	}
+	return folder
+}
+
+// KeyLine returns the file line of a top-level frontmatter key, or 1 if
+// the key is not present
+func (d *Document) KeyLine(key string) int {
+	lines := strings.Split(string(d.Data), "\n")
+	for i := 1; i < len(lines) && i < d.ContentLine; i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if line == "---" {
+			break
+		}
+		if strings.HasPrefix(line, key+":") {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// FileLine converts a 1-based content line to a 1-based file line
+func (d *Document) FileLine(contentLine int) int {
+	return d.ContentLine + contentLine - 1
+}
+
+// Render formats the document's frontmatter and content back into a file
+func (d *Document) Render() ([]byte, error) {
+	if d.Frontmatter == nil {
+		return d.Data, nil
+	}
+
+	return frontmatter.Format(d.Frontmatter, d.Content)
+}
+
+// Slug returns the slug of the note, its path without the extension
+func (d *Document) Slug() string {
+	return strings.TrimSuffix(d.Path, ".md")
+}
+
+// IsNote reports whether a path relative to the notes directory is a note.
+// Hidden files and anything in hidden directories such as .templates are
+// skipped.
+func IsNote(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	if !strings.HasSuffix(relPath, ".md") {
+		return false
+	}
+
+	for _, part := range strings.Split(relPath, "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,39 @@
+package notes
+
+import "strings"
+
+// FenceMarker returns the ``` or ~~~ run opening a fenced code block on a
+// trimmed line, or ""
+func FenceMarker(trimmed string) string {
+	for _, c := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, c+c+c) {
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+			return strings.Repeat(c, n)
+		}
+	}
+	return ""
+}
+
+// FencedLines reports which lines belong to fenced code blocks, fences
+// included. A block is closed by a run of its fence character at least as
+// long as the one that opened it.
+func FencedLines(lines []string) []bool {
+	fenced := make([]bool, len(lines))
+	fence := ""
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			fenced[i] = true
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		} else if marker := FenceMarker(trimmed); marker != "" {
+			fenced[i] = true
+			fence = marker
+		}
+	}
+
+	return fenced
+}
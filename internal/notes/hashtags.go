@@ -0,0 +1,138 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// A hashtag starts a word and must contain a letter, so "#1" and
+	// "C#" are not tags
+	hashtagPattern = regexp.MustCompile(`(^|[\s(\[{,;:!?"'])#([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+	headingPattern = regexp.MustCompile(`^\s{0,3}#{1,6}(\s|$)`)
+	urlPattern     = regexp.MustCompile(`\S+://\S+|\]\([^)]*\)`)
+	codePattern    = regexp.MustCompile("`[^`]*`")
+)
+
+// hashtag is an inline tag found in note content
+type hashtag struct {
+	Tag string
+	// Line is the 0-based content line; Start and End are byte offsets of
+	// "#tag" within it
+	Line       int
+	Start, End int
+}
+
+// InlineTags returns the #hashtags written in content, in order of first
+// appearance. Hashtags in code, headings, and URLs are ignored.
+func InlineTags(content string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+
+	for _, h := range findHashtags(content) {
+		if !seen[h.Tag] {
+			seen[h.Tag] = true
+			tags = append(tags, h.Tag)
+		}
+	}
+
+	return tags
+}
+
+// StripInlineTags removes the given #hashtags from content. Lines made up
+// only of hashtags are removed; elsewhere just the "#" is dropped so the
+// surrounding sentence still reads.
+func StripInlineTags(content string, strip map[string]bool) string {
+	rewrite := make(map[string]string, len(strip))
+	for tag := range strip {
+		rewrite[tag] = ""
+	}
+	return RewriteInlineTags(content, rewrite)
+}
+
+// RewriteInlineTags replaces each #hashtag that is a key of rewrite with
+// its value. Hashtags mapped to "" are stripped as in StripInlineTags.
+func RewriteInlineTags(content string, rewrite map[string]string) string {
+	lines := strings.Split(content, "\n")
+
+	byLine := make(map[int][]hashtag)
+	for _, h := range findHashtags(content) {
+		if _, ok := rewrite[h.Tag]; ok {
+			byLine[h.Line] = append(byLine[h.Line], h)
+		}
+	}
+
+	var out []string
+	for i, line := range lines {
+		found := byLine[i]
+		if len(found) == 0 {
+			out = append(out, line)
+			continue
+		}
+
+		// Work from the end of the line so earlier offsets stay valid.
+		// rest drops stripped hashtags entirely, to tell whether anything
+		// else is left on the line.
+		rest, rewritten := line, line
+		for j := len(found) - 1; j >= 0; j-- {
+			h := found[j]
+			if to := rewrite[h.Tag]; to != "" {
+				rest = rest[:h.Start] + "#" + to + rest[h.End:]
+				rewritten = rewritten[:h.Start] + "#" + to + rewritten[h.End:]
+				continue
+			}
+			rest = rest[:h.Start] + rest[h.End:]
+			rewritten = rewritten[:h.Start] + rewritten[h.Start+1:]
+		}
+
+		if strings.TrimSpace(rest) == "" {
+			continue
+		}
+		out = append(out, rewritten)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// findHashtags returns every hashtag in content outside code blocks,
+// inline code, headings, and URLs
+func findHashtags(content string) []hashtag {
+	var found []hashtag
+	inFence := false
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || headingPattern.MatchString(line) {
+			continue
+		}
+
+		// Blank out code and URLs so offsets stay the same
+		masked := codePattern.ReplaceAllStringFunc(line, blank)
+		masked = urlPattern.ReplaceAllStringFunc(masked, blank)
+
+		for _, m := range hashtagPattern.FindAllStringSubmatchIndex(masked, -1) {
+			tag := strings.TrimRight(masked[m[4]:m[5]], "/-")
+			if !strings.ContainsFunc(tag, unicode.IsLetter) {
+				continue
+			}
+
+			found = append(found, hashtag{
+				Tag:   tag,
+				Line:  i,
+				Start: m[4] - 1,
+				End:   m[4] + len(tag),
+			})
+		}
+	}
+
+	return found
+}
+
+func blank(s string) string {
+	return strings.Repeat(" ", len(s))
+}
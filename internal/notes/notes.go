@@ -1,26 +1,38 @@
 package notes
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/noteid"
 	"github.com/devjasha/noti-vim/pkg/frontmatter"
+	"github.com/devjasha/noti-vim/pkg/slug"
 )
 
 // Note represents a markdown note
 type Note struct {
-	Slug     string    `json:"slug"`
-	Title    string    `json:"title"`
-	Content  string    `json:"content"`
-	Tags     []string  `json:"tags"`
-	Created  time.Time `json:"created"`
-	Modified time.Time `json:"modified"`
-	Folder   string    `json:"folder"`
-	FilePath string    `json:"file_path"`
+	ID      string   `json:"id,omitempty"`
+	Slug    string   `json:"slug"`
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+	// InlineTags are #hashtags written in the content
+	InlineTags []string `json:"inline_tags,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+	// Meta holds the frontmatter fields other than the built-in ones
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+	Created  time.Time              `json:"created"`
+	Modified time.Time              `json:"modified"`
+	Folder   string                 `json:"folder"`
+	FilePath string                 `json:"file_path"`
 }
 
 // ParseNote reads and parses a note from a file
@@ -34,6 +46,10 @@ func ParseNote(path string) (*Note, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not parse frontmatter: %w", err)
 	}
+	if fm == nil {
+		// Plain markdown file without frontmatter
+		fm = &frontmatter.Frontmatter{}
+	}
 
 	// Get file info for modified time
 	fileInfo, err := os.Stat(path)
@@ -60,14 +76,18 @@ func ParseNote(path string) (*Note, error) {
 	}
 
 	note := &Note{
-		Slug:     slug,
-		Title:    fm.Title,
-		Content:  content,
-		Tags:     fm.Tags,
-		Created:  fm.Created,
-		Modified: fileInfo.ModTime(),
-		Folder:   folder,
-		FilePath: path,
+		ID:         fm.ID,
+		Slug:       slug,
+		Title:      fm.Title,
+		Content:    content,
+		Tags:       fm.Tags,
+		InlineTags: InlineTags(content),
+		Aliases:    fm.Aliases,
+		Meta:       fm.Extra,
+		Created:    fm.Created,
+		Modified:   fileInfo.ModTime(),
+		Folder:     folder,
+		FilePath:   path,
 	}
 
 	return note, nil
@@ -87,10 +107,18 @@ func SaveNote(note *Note) error {
 	}
 
 	// Format frontmatter and content
+	tags := note.Tags
+	if tags == nil {
+		// New notes always list their tags
+		tags = []string{}
+	}
 	fm := &frontmatter.Frontmatter{
+		ID:      note.ID,
 		Title:   note.Title,
-		Tags:    note.Tags,
+		Tags:    tags,
 		Created: note.Created,
+		Aliases: note.Aliases,
+		Extra:   note.Meta,
 	}
 
 	data, err := frontmatter.Format(fm, note.Content)
@@ -99,16 +127,33 @@ func SaveNote(note *Note) error {
 	}
 
 	// Write file
-	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+	if err := WriteAtomic(fullPath, data); err != nil {
 		return fmt.Errorf("could not write file: %w", err)
 	}
 
 	return nil
 }
 
-// ListNotes returns all notes, optionally filtered by folder and/or tag
+// Filter selects notes in ListNotesFiltered
+type Filter struct {
+	// Folder limits notes to a folder (not including subfolders)
+	Folder string
+	// Tag limits notes to a tag; nested tags such as "project/alpha" match
+	// the filter "project" unless ExactTag is set
+	Tag      string
+	ExactTag bool
+}
+
+// ListNotes returns all notes, optionally filtered by folder and/or tag.
+// The tag filter also matches nested tags below it.
 func ListNotes(folder, tag string) ([]*Note, error) {
+	return ListNotesFiltered(Filter{Folder: folder, Tag: tag})
+}
+
+// ListNotesFiltered returns the notes selected by filter
+func ListNotesFiltered(filter Filter) ([]*Note, error) {
 	cfg := config.Get()
+	folder, tag := filter.Folder, filter.Tag
 
 	var notes []*Note
 
@@ -139,17 +184,8 @@ func ListNotes(folder, tag string) ([]*Note, error) {
 			return nil
 		}
 
-		if tag != "" {
-			hasTag := false
-			for _, t := range note.Tags {
-				if t == tag {
-					hasTag = true
-					break
-				}
-			}
-			if !hasTag {
-				return nil
-			}
+		if tag != "" && !note.HasTag(tag, filter.ExactTag) {
+			return nil
 		}
 
 		notes = append(notes, note)
@@ -163,12 +199,145 @@ func ListNotes(folder, tag string) ([]*Note, error) {
 	return notes, nil
 }
 
-// GetNote retrieves a single note by slug
+// HasTag reports whether the note has tag in its frontmatter or inline.
+// Unless exact is set, nested tags below tag also count: "project/alpha"
+// has the tag "project".
+func (n *Note) HasTag(tag string, exact bool) bool {
+	for _, t := range n.AllTags() {
+		if TagMatches(t, tag, exact) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tag sources
+const (
+	TagSourceFrontmatter = "frontmatter"
+	TagSourceInline      = "inline"
+	TagSourceBoth        = "both"
+)
+
+// AllTags returns the frontmatter tags followed by inline tags not already
+// in the frontmatter
+func (n *Note) AllTags() []string {
+	all := append([]string{}, n.Tags...)
+	for _, tag := range n.InlineTags {
+		if !containsTag(n.Tags, tag) {
+			all = append(all, tag)
+		}
+	}
+	return all
+}
+
+// TagSource reports where the note declares tag: TagSourceFrontmatter,
+// TagSourceInline, TagSourceBoth, or "" if it does not have it
+func (n *Note) TagSource(tag string) string {
+	fm, inline := containsTag(n.Tags, tag), containsTag(n.InlineTags, tag)
+	switch {
+	case fm && inline:
+		return TagSourceBoth
+	case fm:
+		return TagSourceFrontmatter
+	case inline:
+		return TagSourceInline
+	}
+	return ""
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TagMatches reports whether tag equals filter or, unless exact is set,
+// is nested below it
+func TagMatches(tag, filter string, exact bool) bool {
+	filter = strings.TrimSuffix(filter, "/")
+	if tag == filter {
+		return true
+	}
+	return !exact && strings.HasPrefix(tag, filter+"/")
+}
+
+// TagAncestors returns tag and each of its parents, from the top down:
+// "a/b/c" gives "a", "a/b", "a/b/c"
+func TagAncestors(tag string) []string {
+	parts := strings.Split(strings.Trim(tag, "/"), "/")
+	out := make([]string, 0, len(parts))
+	for i := range parts {
+		out = append(out, strings.Join(parts[:i+1], "/"))
+	}
+	return out
+}
+
+// GetNote retrieves a single note by slug, falling back to a note whose
+// id or one of whose aliases matches
 func GetNote(slug string) (*Note, error) {
 	cfg := config.Get()
 	path := filepath.Join(cfg.NotesDir, slug+".md")
 
-	return ParseNote(path)
+	note, err := ParseNote(path)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return note, err
+	}
+
+	if byID, idErr := FindByID(slug); idErr == nil {
+		return byID, nil
+	}
+	if byAlias, aliasErr := FindByAlias(slug); aliasErr == nil {
+		return byAlias, nil
+	}
+
+	return nil, err
+}
+
+// FindByAlias returns the first note with the given alias, compared
+// case-insensitively
+func FindByAlias(alias string) (*Note, error) {
+	all, err := ListNotes("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range all {
+		if note.HasAlias(alias) {
+			return note, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no note with alias %q", alias)
+}
+
+// HasAlias reports whether name is one of the note's aliases, ignoring case
+func (n *Note) HasAlias(name string) bool {
+	name = strings.TrimSpace(name)
+	for _, alias := range n.Aliases {
+		if strings.EqualFold(strings.TrimSpace(alias), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindByID returns the note with the given id
+func FindByID(id string) (*Note, error) {
+	all, err := ListNotes("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range all {
+		if note.ID != "" && note.ID == id {
+			return note, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no note with id %q", id)
 }
 
 // DeleteNote deletes a note by slug
@@ -183,42 +352,293 @@ func DeleteNote(slug string) error {
 	return nil
 }
 
-// CreateNote creates a new note with the given title and optional parameters
+// ErrNoteExists is returned when creating a note whose file already exists
+var ErrNoteExists = errors.New("note already exists")
+
+// maxCreateAttempts bounds retries when another process takes a slug
+// between choosing it and creating the file
+const maxCreateAttempts = 10
+
+// CreateNote creates a new note with the given title and optional parameters.
+// An existing note is never overwritten: a free slug is chosen according to
+// slug_on_collision, and the file itself is created exclusively.
 func CreateNote(title string, folder string, tags []string) (*Note, error) {
-	// Generate slug from title
-	slug := strings.ToLower(title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	// Remove special characters (keep only alphanumeric and hyphens)
-	slug = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-			return r
-		}
-		return -1
-	}, slug)
-
-	// Add folder to slug if specified
-	if folder != "" {
-		slug = folder + "/" + slug
+	cfg := config.Get()
+
+	id, err := newID(cfg.IDFormat, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
-	note := &Note{
-		Slug:     slug,
-		Title:    title,
-		Content:  "",
-		Tags:     tags,
-		Created:  time.Now(),
-		Modified: time.Now(),
-		Folder:   folder,
+	for attempt := 1; ; attempt++ {
+		slug, err := newSlug(title, folder)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		note := &Note{
+			ID:       id,
+			Slug:     slug,
+			Title:    title,
+			Content:  "",
+			Tags:     tags,
+			Created:  now,
+			Modified: now,
+			Folder:   folder,
+			FilePath: filepath.Join(cfg.NotesDir, slug+".md"),
+		}
+
+		err = createNote(note)
+		if errors.Is(err, ErrNoteExists) && attempt < maxCreateAttempts && cfg.SlugOnCollision != "error" {
+			// Lost a race for the slug; pick the next free one
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return note, nil
+	}
+}
+
+// newID generates an id for a new note, avoiding ids already in use
+func newID(format string, now time.Time) (string, error) {
+	if err := noteid.Validate(format); err != nil {
+		return "", err
+	}
+	if format == noteid.FormatNone {
+		return "", nil
 	}
 
-	// Set filepath
+	var scanErr error
+	id, err := noteid.New(format, now, func(id string) bool {
+		taken, err := idTaken(id)
+		if err != nil {
+			scanErr = err
+		}
+		return taken
+	})
+	if scanErr != nil {
+		return "", scanErr
+	}
+	return id, err
+}
+
+// idTaken reports whether an existing note has the id. Only files that
+// contain the id anywhere are parsed.
+func idTaken(id string) (bool, error) {
+	root := config.Get().NotesDir
+	needle := []byte(id)
+	errFound := errors.New("found")
+
+	err := filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if rel != "." && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !IsNote(rel) {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil || !bytes.Contains(data, needle) {
+			return nil
+		}
+		if fm, _, err := frontmatter.Parse(data); err == nil && fm != nil && fm.ID == id {
+			return errFound
+		}
+		return nil
+	})
+
+	if errors.Is(err, errFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not check note ids: %w", err)
+	}
+	return false, nil
+}
+
+// UsedIDs returns the set of ids used by existing notes
+func UsedIDs() (map[string]bool, error) {
+	all, err := ListNotes("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool)
+	for _, note := range all {
+		if note.ID != "" {
+			ids[note.ID] = true
+		}
+	}
+	return ids, nil
+}
+
+// OpenOrCreateNote returns the note the title would be created as if it
+// already exists, and creates it otherwise. The boolean reports whether
+// the note already existed. With the date and zettel slug strategies the
+// slug of a new note differs every time, so an existing note is found by
+// its title in folder instead.
+func OpenOrCreateNote(title string, folder string, tags []string) (*Note, bool, error) {
+	existing, err := findExisting(title, folder)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+
+	note, err := CreateNote(title, folder, tags)
+	if err != nil {
+		return nil, false, err
+	}
+	return note, false, nil
+}
+
+// findExisting returns the note in folder that creating title would
+// collide with, or nil if there is none
+func findExisting(title, folder string) (*Note, error) {
 	cfg := config.Get()
-	note.FilePath = filepath.Join(cfg.NotesDir, slug+".md")
 
-	// Save note
-	if err := SaveNote(note); err != nil {
+	strategy, err := slug.ParseStrategy(cfg.SlugStrategy)
+	if err != nil {
 		return nil, err
 	}
 
-	return note, nil
+	if strategy != slug.StrategyTitle {
+		candidates, err := ListNotes(folder, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range candidates {
+			if note.Folder == folder && strings.EqualFold(note.Title, title) {
+				return note, nil
+			}
+		}
+		return nil, nil
+	}
+
+	base, err := baseSlug(title, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(cfg.NotesDir, filepath.FromSlash(folder))
+	name, ok := findNote(dir, path.Base(base))
+	if !ok {
+		return nil, nil
+	}
+	return ParseNote(filepath.Join(dir, name))
+}
+
+// createNote writes a new note, failing with ErrNoteExists if its file is
+// already present. The file is reserved with O_EXCL and then filled in by
+// an atomic write, so it is never seen half-written.
+func createNote(note *Note) error {
+	if err := os.MkdirAll(filepath.Dir(note.FilePath), 0755); err != nil {
+		return fmt.Errorf("could not create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(note.FilePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("%w: %s", ErrNoteExists, note.Slug)
+	}
+	if err != nil {
+		return fmt.Errorf("could not create file: %w", err)
+	}
+	f.Close()
+
+	if err := SaveNote(note); err != nil {
+		os.Remove(note.FilePath)
+		return err
+	}
+
+	return nil
+}
+
+// baseSlug generates the slug for a new note in folder according to the
+// configured strategy, without checking for existing notes
+func baseSlug(title, folder string) (string, error) {
+	cfg := config.Get()
+
+	strategy, err := slug.ParseStrategy(cfg.SlugStrategy)
+	if err != nil {
+		return "", err
+	}
+
+	name := slug.Generate(title, slug.Options{
+		Strategy:  strategy,
+		MaxLength: cfg.SlugMaxLength,
+	})
+
+	if folder != "" {
+		return folder + "/" + name, nil
+	}
+	return name, nil
+}
+
+// newSlug generates the slug for a new note in folder, resolving
+// collisions with existing notes
+func newSlug(title, folder string) (string, error) {
+	cfg := config.Get()
+
+	base, err := baseSlug(title, folder)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(cfg.NotesDir, filepath.FromSlash(folder))
+	exists := func(candidate string) bool {
+		_, ok := findNote(dir, candidate)
+		return ok
+	}
+
+	name := path.Base(base)
+	switch cfg.SlugOnCollision {
+	case "", "suffix":
+		name = slug.Unique(name, exists)
+	case "error":
+		if exists(name) {
+			return "", fmt.Errorf("%w: %s", ErrNoteExists, base)
+		}
+	default:
+		return "", fmt.Errorf("unknown slug_on_collision %q (use \"suffix\" or \"error\")", cfg.SlugOnCollision)
+	}
+
+	if folder != "" {
+		return folder + "/" + name, nil
+	}
+	return name, nil
+}
+
+// findNote returns the file name of the note called name in dir. Names are
+// compared case-insensitively so notes stay distinct on case-insensitive
+// file systems.
+func findNote(dir, name string) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name(), name+".md") {
+			return entry.Name(), true
+		}
+	}
+	return "", false
 }
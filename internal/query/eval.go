@@ -0,0 +1,380 @@
+package query
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devjasha/noti-vim/internal/links"
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+// Fields computed for every note, in addition to its frontmatter
+var Fields = []string{
+	"slug", "title", "folder", "id", "tags", "aliases",
+	"created", "modified", "words", "links", "backlinks",
+}
+
+// Row is a note with its computed fields
+type Row struct {
+	Note *notes.Note
+	// Links counts the links to other notes; Backlinks counts the notes
+	// linking here
+	Links, Backlinks int
+}
+
+// Get returns the value of a field, or nil if the note does not have it.
+// Computed fields take precedence over frontmatter keys of the same name;
+// frontmatter keys are matched case-insensitively.
+func (r Row) Get(field string) interface{} {
+	n := r.Note
+
+	switch field {
+	case "slug":
+		return n.Slug
+	case "title":
+		return n.Title
+	case "folder":
+		return n.Folder
+	case "id":
+		return n.ID
+	case "tags":
+		return n.AllTags()
+	case "aliases":
+		return n.Aliases
+	case "created":
+		if n.Created.IsZero() {
+			return nil
+		}
+		return n.Created
+	case "modified":
+		return n.Modified
+	case "words":
+		return len(strings.Fields(n.Content))
+	case "links":
+		return r.Links
+	case "backlinks":
+		return r.Backlinks
+	}
+
+	if v, ok := n.Meta[field]; ok {
+		return v
+	}
+	for key, v := range n.Meta {
+		if strings.EqualFold(key, field) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Result holds the selected fields of the matching notes
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Run evaluates the query against all notes. Link counts are computed over
+// the whole set, so backlinks from notes outside FROM still count.
+func (q *Query) Run(all []*notes.Note) *Result {
+	rows := buildRows(all)
+
+	var matched []Row
+	for _, row := range rows {
+		if q.matchSources(row.Note) && (q.Where == nil || q.Where.eval(row)) {
+			matched = append(matched, row)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		for _, key := range q.Sort {
+			c := compareForSort(matched[i].Get(key.Field), matched[j].Get(key.Field), key.Desc)
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+
+	result := &Result{Columns: q.Select}
+	for _, row := range matched {
+		values := make([]interface{}, len(q.Select))
+		for i, field := range q.Select {
+			values[i] = row.Get(field)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+
+	return result
+}
+
+func (q *Query) matchSources(note *notes.Note) bool {
+	for _, source := range q.Sources {
+		switch source.Kind {
+		case SourceFolder:
+			if note.Folder != source.Value && !strings.HasPrefix(note.Folder, source.Value+"/") {
+				return false
+			}
+		case SourceTag:
+			if !note.HasTag(source.Value, false) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// buildRows computes link counts for every note
+func buildRows(all []*notes.Note) []Row {
+	resolver := links.NewResolver(all)
+	rows := make([]Row, len(all))
+	index := make(map[*notes.Note]int, len(all))
+	for i, note := range all {
+		rows[i].Note = note
+		index[note] = i
+	}
+
+	for i, note := range all {
+		linked := make(map[*notes.Note]bool)
+		for _, link := range links.Extract(note.Content) {
+			rows[i].Links++
+			if target, ok := resolver.Resolve(link, note.Folder); ok && target != note && !linked[target] {
+				linked[target] = true
+				rows[index[target]].Backlinks++
+			}
+		}
+	}
+
+	return rows
+}
+
+func (e compareExpr) eval(row Row) bool {
+	left, right := e.left.value(row), e.right.value(row)
+
+	switch e.op {
+	case "=":
+		return matches(left, right)
+	case "!=":
+		return !matches(left, right)
+	case "contains":
+		return contains(left, right)
+	}
+
+	if isEmpty(left) || isEmpty(right) {
+		return false
+	}
+	c, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func (e andExpr) eval(row Row) bool { return e.left.eval(row) && e.right.eval(row) }
+func (e orExpr) eval(row Row) bool  { return e.left.eval(row) || e.right.eval(row) }
+func (e notExpr) eval(row Row) bool { return !e.expr.eval(row) }
+
+func (e truthExpr) eval(row Row) bool {
+	v := e.operand.value(row)
+	switch v := normalize(v).(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	}
+	return !isEmpty(v)
+}
+
+// matches reports whether a equals b; a list matches if any item does
+func matches(a, b interface{}) bool {
+	if isEmpty(a) || isEmpty(b) {
+		return isEmpty(a) && isEmpty(b)
+	}
+	if list, ok := normalize(a).([]interface{}); ok {
+		for _, item := range list {
+			if matches(item, b) {
+				return true
+			}
+		}
+		return false
+	}
+	c, ok := compare(a, b)
+	return ok && c == 0
+}
+
+// contains reports whether list a has item b, or string a contains b
+func contains(a, b interface{}) bool {
+	if isEmpty(a) || isEmpty(b) {
+		return false
+	}
+	if list, ok := normalize(a).([]interface{}); ok {
+		return matches(list, b)
+	}
+	s, ok := normalize(a).(string)
+	if !ok {
+		return false
+	}
+	return strings.Contains(strings.ToLower(s), strings.ToLower(Format(b)))
+}
+
+// compare orders two values. Numbers and dates compare by value, strings
+// case-insensitively; a string is converted when the other side is a number
+// or a date.
+func compare(a, b interface{}) (int, bool) {
+	a, b = normalize(a), normalize(b)
+
+	switch x := a.(type) {
+	case float64:
+		if y, ok := toNumber(b); ok {
+			return compareFloat(x, y), true
+		}
+	case time.Time:
+		if y, ok := toDate(b); ok {
+			return x.Compare(y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareBool(x, y), true
+		}
+		return 0, false
+	case string:
+		switch y := b.(type) {
+		case float64:
+			if x, ok := toNumber(x); ok {
+				return compareFloat(x, y), true
+			}
+		case time.Time:
+			if x, ok := toDate(x); ok {
+				return x.Compare(y), true
+			}
+		case string:
+			return strings.Compare(strings.ToLower(x), strings.ToLower(y)), true
+		}
+	}
+
+	return 0, false
+}
+
+// compareForSort orders values for SORT. Missing values go last in either
+// direction, and values of different types fall back to comparing their
+// text.
+func compareForSort(a, b interface{}, desc bool) int {
+	emptyA, emptyB := isEmpty(a), isEmpty(b)
+	switch {
+	case emptyA && emptyB:
+		return 0
+	case emptyA:
+		return 1
+	case emptyB:
+		return -1
+	}
+
+	c, ok := compare(a, b)
+	if !ok {
+		c = strings.Compare(strings.ToLower(Format(a)), strings.ToLower(Format(b)))
+	}
+	if desc {
+		return -c
+	}
+	return c
+}
+
+// normalize converts numbers to float64 and string lists to []interface{}
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	}
+	return v
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := normalize(v).(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case time.Time:
+		return v.IsZero()
+	}
+	return false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func toDate(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		return parseTime(strings.TrimSpace(v))
+	}
+	return time.Time{}, false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+// isWhole reports whether f has no fractional part
+func isWhole(f float64) bool {
+	return f == math.Trunc(f) && math.Abs(f) < 1e15
+}
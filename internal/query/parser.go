@@ -0,0 +1,384 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query is a parsed query
+type Query struct {
+	// Sources restrict the notes considered; all of them must match
+	Sources []Source
+	// Where filters notes; nil matches every note
+	Where Expr
+	Sort  []SortKey
+	// Select lists the fields to output
+	Select []string
+	// Limit caps the number of rows; 0 means no limit
+	Limit int
+}
+
+// Source is a FROM term such as folder:projects or tag:work
+type Source struct {
+	Kind  string
+	Value string
+}
+
+// Source kinds
+const (
+	SourceFolder = "folder"
+	SourceTag    = "tag"
+)
+
+// SortKey orders rows by a field
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// DefaultSelect is used when a query has no SELECT clause
+var DefaultSelect = []string{"slug", "title"}
+
+// Expr is a WHERE expression
+type Expr interface {
+	eval(row Row) bool
+}
+
+// operand is one side of a comparison: a field or a literal
+type operand struct {
+	field   string
+	literal interface{}
+}
+
+func (o operand) value(row Row) interface{} {
+	if o.field != "" {
+		return row.Get(o.field)
+	}
+	return o.literal
+}
+
+type compareExpr struct {
+	left, right operand
+	op          string
+}
+
+type andExpr struct{ left, right Expr }
+type orExpr struct{ left, right Expr }
+type notExpr struct{ expr Expr }
+
+// truthExpr is a bare operand, true when the value is set and not false,
+// zero, or empty
+type truthExpr struct{ operand operand }
+
+var clauses = []string{"FROM", "WHERE", "SORT", "SELECT", "LIMIT"}
+
+// Parse parses a query. Clauses may appear in any order, each at most once:
+//
+//	FROM folder:projects tag:work
+//	WHERE status = "active" AND (due < 2024-06-01 OR NOT owner)
+//	SORT due ASC, title
+//	SELECT title, owner, due
+//	LIMIT 10
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	q := &Query{}
+	seen := make(map[string]bool)
+
+	for p.peek().kind != tokEOF {
+		tok := p.next()
+		clause := strings.ToUpper(tok.text)
+		if tok.kind != tokIdent || !isClause(clause) {
+			return nil, fmt.Errorf("expected FROM, WHERE, SORT, SELECT, or LIMIT, got %s", tok)
+		}
+		if seen[clause] {
+			return nil, fmt.Errorf("%s given more than once", clause)
+		}
+		seen[clause] = true
+
+		switch clause {
+		case "FROM":
+			err = p.parseFrom(q)
+		case "WHERE":
+			q.Where, err = p.parseOr()
+		case "SORT":
+			err = p.parseSort(q)
+		case "SELECT":
+			q.Select, err = p.parseFields()
+		case "LIMIT":
+			err = p.parseLimit(q)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", clause, err)
+		}
+	}
+
+	if len(q.Select) == 0 {
+		q.Select = DefaultSelect
+	}
+
+	return q, nil
+}
+
+func isClause(word string) bool {
+	for _, c := range clauses {
+		if word == c {
+			return true
+		}
+	}
+	return false
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// atClauseEnd reports whether the next token ends the current clause
+func (p *parser) atClauseEnd() bool {
+	tok := p.peek()
+	return tok.kind == tokEOF || (tok.kind == tokIdent && isClause(strings.ToUpper(tok.text)))
+}
+
+func (p *parser) parseFrom(q *Query) error {
+	for !p.atClauseEnd() {
+		tok := p.next()
+		if tok.kind == tokComma || tok.keyword("AND") {
+			continue
+		}
+
+		source := Source{Kind: SourceFolder}
+		switch tok.kind {
+		case tokString:
+			source.Value = tok.text
+		case tokIdent:
+			if p.peek().kind == tokColon {
+				p.next()
+				source.Kind = strings.ToLower(tok.text)
+				if source.Kind != SourceFolder && source.Kind != SourceTag {
+					return fmt.Errorf("unknown source %q (use folder: or tag:)", tok.text)
+				}
+				value := p.next()
+				if value.kind != tokIdent && value.kind != tokString && value.kind != tokNumber {
+					return fmt.Errorf("expected a %s name, got %s", source.Kind, value)
+				}
+				source.Value = value.text
+			} else {
+				source.Value = tok.text
+			}
+		default:
+			return fmt.Errorf("expected a folder or tag, got %s", tok)
+		}
+
+		source.Value = strings.Trim(source.Value, "/")
+		if source.Kind == SourceTag {
+			source.Value = strings.TrimPrefix(source.Value, "#")
+		}
+		q.Sources = append(q.Sources, source)
+	}
+
+	if len(q.Sources) == 0 {
+		return fmt.Errorf("expected a folder or tag")
+	}
+	return nil
+}
+
+func (p *parser) parseSort(q *Query) error {
+	for {
+		tok := p.next()
+		if tok.kind != tokIdent || isClause(strings.ToUpper(tok.text)) {
+			return fmt.Errorf("expected a field name, got %s", tok)
+		}
+		key := SortKey{Field: strings.ToLower(tok.text)}
+
+		if p.peek().keyword("ASC") {
+			p.next()
+		} else if p.peek().keyword("DESC") {
+			p.next()
+			key.Desc = true
+		}
+		q.Sort = append(q.Sort, key)
+
+		if p.peek().kind != tokComma {
+			return nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseFields() ([]string, error) {
+	var fields []string
+	for {
+		tok := p.next()
+		if tok.kind != tokIdent || isClause(strings.ToUpper(tok.text)) {
+			return nil, fmt.Errorf("expected a field name, got %s", tok)
+		}
+		fields = append(fields, strings.ToLower(tok.text))
+
+		if p.peek().kind != tokComma {
+			return fields, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseLimit(q *Query) error {
+	tok := p.next()
+	n, err := strconv.Atoi(tok.text)
+	if tok.kind != tokNumber || err != nil || n < 1 {
+		return fmt.Errorf("expected a positive number, got %s", tok)
+	}
+	q.Limit = n
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.peek().keyword("NOT") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	if p.peek().kind == tokLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, fmt.Errorf("expected \")\", got %s", tok)
+		}
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	var op string
+	switch {
+	case tok.kind == tokOp:
+		op = tok.text
+		if op == "==" {
+			op = "="
+		}
+	case tok.keyword("CONTAINS"):
+		op = "contains"
+	default:
+		return truthExpr{left}, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return compareExpr{left: left, right: right, op: op}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokString:
+		return operand{literal: tok.text}, nil
+	case tokNumber:
+		if n, err := strconv.ParseFloat(tok.text, 64); err == nil {
+			return operand{literal: n}, nil
+		}
+		if t, ok := parseTime(tok.text); ok {
+			return operand{literal: t}, nil
+		}
+		return operand{}, fmt.Errorf("invalid number or date %s", tok)
+	case tokIdent:
+		switch strings.ToUpper(tok.text) {
+		case "TRUE":
+			return operand{literal: true}, nil
+		case "FALSE":
+			return operand{literal: false}, nil
+		case "NULL":
+			return operand{literal: nil}, nil
+		case "AND", "OR", "NOT", "CONTAINS":
+			return operand{}, fmt.Errorf("expected a field or value, got %s", tok)
+		}
+		if isClause(strings.ToUpper(tok.text)) {
+			return operand{}, fmt.Errorf("expected a field or value, got %s", tok)
+		}
+		return operand{field: strings.ToLower(tok.text)}, nil
+	default:
+		return operand{}, fmt.Errorf("expected a field or value, got %s", tok)
+	}
+}
+
+// parseTime parses the date formats accepted in queries and frontmatter.
+// Plain dates are midnight UTC, as YAML decodes them.
+func parseTime(s string) (time.Time, bool) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,124 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+func testNotes() []*notes.Note {
+	day := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02", s)
+		return t
+	}
+
+	return []*notes.Note{
+		{Slug: "projects/alpha", Title: "Alpha", Folder: "projects", Tags: []string{"work", "project/alpha"},
+			Meta: map[string]interface{}{"status": "active", "due": day("2024-03-01"), "priority": 2}, Created: day("2024-01-10"),
+			Content: "See [[Beta]]."},
+		{Slug: "projects/beta", Title: "Beta", Folder: "projects", Tags: []string{"work"},
+			Meta: map[string]interface{}{"status": "done", "due": day("2024-02-01"), "priority": 1}, Created: day("2024-01-05")},
+		{Slug: "projects/sub/gamma", Title: "Gamma", Folder: "projects/sub", Tags: []string{"idea"},
+			Meta: map[string]interface{}{"status": "Active", "priority": 3}, Created: day("2024-02-20"),
+			Content: "Also [[Beta]]."},
+		{Slug: "journal/today", Title: "Today", Folder: "journal", Created: day("2024-03-05")},
+	}
+}
+
+// slugs runs the query and returns the slugs of the rows, which must be
+// selected first
+func slugs(t *testing.T, input string) string {
+	t.Helper()
+	q, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q) = %v", input, err)
+	}
+	q.Select = []string{"slug"}
+
+	var out []string
+	for _, row := range q.Run(testNotes()).Rows {
+		out = append(out, row[0].(string))
+	}
+	return strings.Join(out, " ")
+}
+
+func TestQueries(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"", "projects/alpha projects/beta projects/sub/gamma journal/today"},
+		{"FROM projects", "projects/alpha projects/beta projects/sub/gamma"},
+		{"FROM folder:projects/sub", "projects/sub/gamma"},
+		{"FROM tag:project", "projects/alpha"},
+		{"FROM projects, tag:work", "projects/alpha projects/beta"},
+		{`WHERE status = "active"`, "projects/alpha projects/sub/gamma"},
+		{`WHERE status != "active"`, "projects/beta journal/today"},
+		{"WHERE priority >= 2 SORT priority DESC", "projects/sub/gamma projects/alpha"},
+		{"WHERE due < 2024-02-15", "projects/beta"},
+		{"WHERE due", "projects/alpha projects/beta"},
+		{"WHERE NOT due", "projects/sub/gamma journal/today"},
+		{`WHERE tags contains "work" AND NOT (status = "done" OR priority > 2)`, "projects/alpha"},
+		{`WHERE title contains "a" OR created > 2024-03-01`, "projects/alpha projects/beta projects/sub/gamma journal/today"},
+		{"WHERE backlinks > 0", "projects/beta"},
+		{"WHERE links = 1 SORT created", "projects/alpha projects/sub/gamma"},
+		{"SORT due DESC", "projects/alpha projects/beta projects/sub/gamma journal/today"},
+		{"SORT folder, title DESC LIMIT 3", "journal/today projects/beta projects/alpha"},
+		{"limit 1 sort created", "projects/beta"},
+	}
+
+	for _, tt := range tests {
+		if got := slugs(t, tt.query); got != tt.want {
+			t.Errorf("%q = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseSelect(t *testing.T) {
+	q, err := Parse("SELECT title, due LIMIT 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(q.Select, ",") != "title,due" || q.Limit != 5 {
+		t.Errorf("Parse() = select %v limit %d, want title,due limit 5", q.Select, q.Limit)
+	}
+
+	q, err = Parse("FROM projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(q.Select, ",") != strings.Join(DefaultSelect, ",") {
+		t.Errorf("Parse() without SELECT = %v, want %v", q.Select, DefaultSelect)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"WHERE",
+		"WHERE status =",
+		`WHERE (status = "done"`,
+		`WHERE status = "done" extra`,
+		"SORT",
+		"LIMIT many",
+		"LIMIT -1",
+		"FROM projects FROM journal",
+		"projects",
+		`WHERE title = "unterminated`,
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestFieldOperands(t *testing.T) {
+	// Bare words on either side of a comparison are fields, so a note can
+	// be compared with itself. Missing fields are null and equal each other.
+	if got, want := slugs(t, "WHERE status = done"), "journal/today"; got != want {
+		t.Errorf("status = done matched %q, want %q", got, want)
+	}
+	if got, want := slugs(t, "WHERE created < due"), "projects/alpha projects/beta"; got != want {
+		t.Errorf("created < due = %s, want %s", got, want)
+	}
+}
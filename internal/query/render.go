@@ -0,0 +1,197 @@
+package query
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+)
+
+// Output formats
+const (
+	FormatTable    = "table"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Render writes the result in the given format
+func (r *Result) Render(w io.Writer, format string) error {
+	switch format {
+	case FormatTable, "":
+		return r.renderTable(w)
+	case FormatCSV:
+		return r.renderCSV(w)
+	case FormatJSON:
+		return r.renderJSON(w)
+	case FormatMarkdown, "md":
+		return r.renderMarkdown(w)
+	default:
+		return fmt.Errorf("unknown format %q (use %s, %s, %s, or %s)", format, FormatTable, FormatCSV, FormatJSON, FormatMarkdown)
+	}
+}
+
+// Format formats a value for display. Dates without a time of day print as
+// 2006-01-02 and lists as comma-separated items.
+func Format(v interface{}) string {
+	switch v := normalize(v).(type) {
+	case nil:
+		return ""
+	case float64:
+		if isWhole(v) {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		if v.Equal(v.Truncate(24*time.Hour)) && v.Location() == time.UTC {
+			return v.Format("2006-01-02")
+		}
+		return v.Local().Format("2006-01-02 15:04")
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = Format(item)
+		}
+		return strings.Join(items, ", ")
+	default:
+		return frontmatter.FormatValue(v)
+	}
+}
+
+func (r *Result) renderTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(r.Columns))
+	for i, column := range r.Columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			// Keep each row on one line
+			cells[i] = strings.Join(strings.Fields(Format(v)), " ")
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func (r *Result) renderCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.Columns); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = Format(v)
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *Result) renderMarkdown(w io.Writer) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	fmt.Fprintf(w, "| %s |\n", strings.Join(r.Columns, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(r.Columns)))
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = escape.Replace(Format(v))
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonRow marshals a row as an object with keys in column order
+type jsonRow struct {
+	columns []string
+	values  []interface{}
+}
+
+func (r jsonRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(JSONValue(r.values[i]))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// JSONValue keeps numbers, booleans, and lists typed and formats dates
+// the same way as the other formats
+func JSONValue(v interface{}) interface{} {
+	switch v := normalize(v).(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		if v.Equal(v.Truncate(24*time.Hour)) && v.Location() == time.UTC {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = JSONValue(item)
+		}
+		return items
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = JSONValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (r *Result) renderJSON(w io.Writer) error {
+	rows := make([]jsonRow, len(r.Rows))
+	for i, values := range r.Rows {
+		rows[i] = jsonRow{columns: r.Columns, values: values}
+	}
+
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,297 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options control how the search text is matched
+type Options struct {
+	// Regex treats the text as an RE2 regular expression
+	Regex bool
+	// Fuzzy matches words within a small edit distance, so typos still match
+	Fuzzy bool
+	// CaseSensitive disables case folding
+	CaseSensitive bool
+	// WholeWord only matches at word boundaries
+	WholeWord bool
+	// Analyzers also match the text word by word after stemming and
+	// folding, so "running" finds "run". They are not used for regular
+	// expressions, fuzzy, case-sensitive, or whole-word searches.
+	Analyzers []*Analyzer
+	// SnippetWindow is the number of characters shown around a match;
+	// DefaultSnippetWindow if zero
+	SnippetWindow int
+	// MaxMatches caps the matches returned per note; zero means no cap
+	MaxMatches int
+}
+
+// span is a byte range within a line
+type span struct {
+	start, end int
+}
+
+// matcher returns the matches of the search text in s, in order and
+// without overlaps
+type matcher func(s string) []span
+
+// newMatcher builds the matcher for text according to opts
+func newMatcher(text string, opts Options) (matcher, error) {
+	if opts.Regex && opts.Fuzzy {
+		return nil, fmt.Errorf("regex and fuzzy matching cannot be combined")
+	}
+
+	if opts.Fuzzy {
+		return fuzzyMatcher(text, opts), nil
+	}
+
+	pattern := text
+	if !opts.Regex {
+		pattern = regexp.QuoteMeta(text)
+	}
+	if !opts.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	literal := func(s string) []span {
+		var spans []span
+		for _, m := range re.FindAllStringIndex(s, -1) {
+			if m[0] == m[1] {
+				continue
+			}
+			if opts.WholeWord && !atWordBoundary(s, m[0], m[1]) {
+				continue
+			}
+			spans = append(spans, span{m[0], m[1]})
+		}
+		return spans
+	}
+
+	if opts.Regex || opts.CaseSensitive || opts.WholeWord || len(opts.Analyzers) == 0 {
+		return literal, nil
+	}
+
+	analyzed := analyzedMatcher(text, opts.Analyzers)
+	return func(s string) []span {
+		return mergeSpans(append(literal(s), analyzed(s)...))
+	}, nil
+}
+
+// mergeSpans sorts spans and joins those that overlap
+func mergeSpans(spans []span) []span {
+	if len(spans) < 2 {
+		return spans
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			if s.end > last.end {
+				last.end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// analyzedMatcher matches the terms of text as a phrase among the terms of
+// a line, for any of the analyzers. Stop words are skipped on both sides.
+func analyzedMatcher(text string, analyzers []*Analyzer) matcher {
+	queries := make([][]string, len(analyzers))
+	for i, a := range analyzers {
+		queries[i] = a.Terms(text)
+	}
+
+	return func(s string) []span {
+		var spans []span
+		for i, a := range analyzers {
+			query := queries[i]
+			if len(query) == 0 {
+				continue
+			}
+
+			tokens := a.Analyze(s)
+			for j := 0; j+len(query) <= len(tokens); j++ {
+				matched := true
+				for k, term := range query {
+					if tokens[j+k].Text != term {
+						matched = false
+						break
+					}
+				}
+				if matched {
+					spans = append(spans, span{tokens[j].Start, tokens[j+len(query)-1].End})
+					j += len(query) - 1
+				}
+			}
+		}
+		return mergeSpans(spans)
+	}
+}
+
+// atWordBoundary reports whether s[start:end] is not part of a longer word
+func atWordBoundary(s string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(s[:start])
+		if isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[end:])
+		if isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// word is a word of a line with its byte offsets
+type word struct {
+	text string
+	span
+}
+
+// splitWords returns the words of s
+func splitWords(s string) []word {
+	var words []word
+	start := -1
+
+	for i, r := range s {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, word{s[start:i], span{start, i}})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{s[start:], span{start, len(s)}})
+	}
+
+	return words
+}
+
+// fuzzyMatcher matches consecutive words that are each within a few edits
+// of the corresponding query word. Unless WholeWord is set, a query word
+// may also match the start of a longer word.
+func fuzzyMatcher(text string, opts Options) matcher {
+	fold := func(s string) string {
+		if opts.CaseSensitive {
+			return s
+		}
+		return strings.ToLower(s)
+	}
+
+	var query []string
+	for _, w := range splitWords(text) {
+		query = append(query, fold(w.text))
+	}
+
+	return func(s string) []span {
+		if len(query) == 0 {
+			return nil
+		}
+
+		var spans []span
+		words := splitWords(s)
+		for i := 0; i+len(query) <= len(words); i++ {
+			matched := true
+			for j, q := range query {
+				if !fuzzyEqual(q, fold(words[i+j].text), opts.WholeWord) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				spans = append(spans, span{words[i].start, words[i+len(query)-1].end})
+				i += len(query) - 1
+			}
+		}
+		return spans
+	}
+}
+
+// fuzzyEqual reports whether w is within the allowed edit distance of q.
+// Unless whole is set, the start of a longer word also counts, so "deplo"
+// matches "deployment".
+func fuzzyEqual(q, w string, whole bool) bool {
+	limit := maxEdits(q)
+	if editDistance(q, w) <= limit {
+		return true
+	}
+	if whole {
+		return false
+	}
+
+	qr, wr := []rune(q), []rune(w)
+	return len(wr) > len(qr) && editDistance(q, string(wr[:len(qr)])) <= limit
+}
+
+// maxEdits is the number of typos tolerated in a word: none for very short
+// words, one for words up to five letters, and two beyond that
+func maxEdits(q string) int {
+	switch n := utf8.RuneCountInString(q); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions, and transpositions of adjacent
+// runes each count as one edit
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev2 := make([]int, len(br)+1)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(br)]
+}
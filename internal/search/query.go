@@ -0,0 +1,259 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+// Query is a parsed search query. Words are searched for as a phrase;
+// qualifiers narrow the notes searched:
+//
+//	tag:inbox      notes with the tag (or a nested tag below it)
+//	folder:work    notes in the folder or its subfolders
+//	-tag:done      notes without the tag
+//	-folder:old    notes outside the folder
+//	section:Todo   lines under a heading, or a heading path ("API > Errors")
+//	-section:Log   lines outside a heading
+//	in:code        lines of fenced code blocks
+//	in:prose       lines outside code blocks
+//	lang:go        lines of code blocks in a language
+//	-lang:sh       lines outside code blocks in a language
+//	-draft         notes not containing the word
+//
+// Values with spaces can be quoted: tag:"to read". Quoted text such as
+// "-draft" is searched for as written.
+type Query struct {
+	// Text is the phrase to search for; empty matches every note that passes
+	// the qualifiers
+	Text       string
+	Tags       []string
+	NotTags    []string
+	Folders    []string
+	NotFolders []string
+	// Sections and NotSections scope the search to lines under, or outside,
+	// the given headings
+	Sections    []string
+	NotSections []string
+	// In is "code" or "prose" to search only code blocks or only text
+	In string
+	// Langs and NotLangs scope the search to code blocks in, or not in, the
+	// given languages
+	Langs    []string
+	NotLangs []string
+	// Exclude lists words that must not appear in the note
+	Exclude []string
+}
+
+// ParseQuery parses a search query
+func ParseQuery(s string) Query {
+	var q Query
+	var words []string
+
+	for _, t := range splitTerms(s) {
+		term := t.text
+		if t.quoted {
+			words = append(words, term)
+			continue
+		}
+
+		negated := strings.HasPrefix(term, "-") && len(term) > 1
+		if negated {
+			term = term[1:]
+		}
+
+		key, value, qualified := strings.Cut(term, ":")
+		key = strings.ToLower(key)
+		switch key {
+		case "tag", "folder", "section", "lang":
+		case "in":
+			value = strings.ToLower(value)
+			qualified = qualified && (value == InCode || value == InProse)
+		default:
+			qualified = false
+		}
+		qualified = qualified && value != ""
+
+		switch {
+		case qualified && key == "tag":
+			value = strings.TrimPrefix(value, "#")
+			if negated {
+				q.NotTags = append(q.NotTags, value)
+			} else {
+				q.Tags = append(q.Tags, value)
+			}
+		case qualified && key == "folder":
+			value = strings.Trim(value, "/")
+			if negated {
+				q.NotFolders = append(q.NotFolders, value)
+			} else {
+				q.Folders = append(q.Folders, value)
+			}
+		case qualified && key == "section":
+			if negated {
+				q.NotSections = append(q.NotSections, value)
+			} else {
+				q.Sections = append(q.Sections, value)
+			}
+		case qualified && key == "lang":
+			if negated {
+				q.NotLangs = append(q.NotLangs, strings.ToLower(value))
+			} else {
+				q.Langs = append(q.Langs, strings.ToLower(value))
+			}
+		case qualified && key == "in":
+			// -in:code is the same as in:prose
+			if negated == (value == InCode) {
+				q.In = InProse
+			} else {
+				q.In = InCode
+			}
+		case negated:
+			q.Exclude = append(q.Exclude, term)
+		default:
+			words = append(words, term)
+		}
+	}
+
+	q.Text = strings.Join(words, " ")
+	return q
+}
+
+// Matches reports whether a note passes the query's qualifiers. The
+// phrase and the excluded words are matched separately, by the search
+// options.
+func (q Query) Matches(note *notes.Note) bool {
+	for _, tag := range q.Tags {
+		if !note.HasTag(tag, false) {
+			return false
+		}
+	}
+	for _, tag := range q.NotTags {
+		if note.HasTag(tag, false) {
+			return false
+		}
+	}
+
+	if len(q.Folders) > 0 {
+		in := false
+		for _, folder := range q.Folders {
+			if inFolder(note, folder) {
+				in = true
+				break
+			}
+		}
+		if !in {
+			return false
+		}
+	}
+	for _, folder := range q.NotFolders {
+		if inFolder(note, folder) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inFolder reports whether note is in folder or one of its subfolders
+func inFolder(note *notes.Note, folder string) bool {
+	return note.Folder == folder || strings.HasPrefix(note.Folder, folder+"/")
+}
+
+// term is a word of a query; quoted terms started with a double quote
+type term struct {
+	text   string
+	quoted bool
+}
+
+// splitTerms splits s at whitespace, keeping double-quoted text together
+// and dropping the quotes
+func splitTerms(s string) []term {
+	var terms []term
+	var b strings.Builder
+	var current term
+	inQuote, started := false, false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if !started {
+				current.quoted = true
+			}
+			inQuote = !inQuote
+			started = true
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			if started && b.Len() > 0 {
+				current.text = b.String()
+				terms = append(terms, current)
+			}
+			b.Reset()
+			current = term{}
+			started = false
+		default:
+			b.WriteRune(r)
+			started = true
+		}
+	}
+	if started && b.Len() > 0 {
+		current.text = b.String()
+		terms = append(terms, current)
+	}
+
+	return terms
+}
+
+// Values of the in: qualifier
+const (
+	InCode  = "code"
+	InProse = "prose"
+)
+
+// scoped reports whether the query is limited to some sections or to code,
+// which leaves out titles and tags
+func (q Query) scoped() bool {
+	return len(q.Sections) > 0 || len(q.NotSections) > 0 || q.In == InCode || len(q.Langs) > 0
+}
+
+// matchesLine reports whether a line is in scope
+func (q Query) matchesLine(info lineInfo) bool {
+	for _, section := range q.Sections {
+		if !inSection(info.Headings, section) {
+			return false
+		}
+	}
+	for _, section := range q.NotSections {
+		if inSection(info.Headings, section) {
+			return false
+		}
+	}
+
+	switch q.In {
+	case InCode:
+		if !info.Code {
+			return false
+		}
+	case InProse:
+		if info.Code {
+			return false
+		}
+	}
+
+	if len(q.Langs) > 0 && !(info.Code && containsFold(q.Langs, info.Lang)) {
+		return false
+	}
+	if info.Code && containsFold(q.NotLangs, info.Lang) {
+		return false
+	}
+
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/devjasha/noti-vim/internal/config"
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+// vectorCacheFile holds term counts per note in the vault's state directory
+const vectorCacheFile = "search-vectors.json"
+
+// vectorCacheVersion changes whenever the cached terms would differ
+const vectorCacheVersion = 1
+
+// sharedTermCount is the number of shared terms reported per related note
+const sharedTermCount = 5
+
+// RelatedNote is a note similar to another one
+type RelatedNote struct {
+	Note *notes.Note `json:"note"`
+	// Score is the cosine similarity of the notes' TF-IDF vectors, from 0 to 1
+	Score float64 `json:"score"`
+	// SharedTerms are the words contributing most to the score
+	SharedTerms []string `json:"shared_terms"`
+}
+
+// termCounts are the term frequencies of one note
+type termCounts struct {
+	// ModTime and Size identify the version of the note that was counted
+	ModTime int64          `json:"mod_time"`
+	Size    int            `json:"size"`
+	Terms   map[string]int `json:"terms"`
+	// Words maps each term to the word it was first seen as, for display
+	Words map[string]string `json:"words"`
+}
+
+type vectorCache struct {
+	Version  int                    `json:"version"`
+	Analyzer string                 `json:"analyzer"`
+	Notes    map[string]*termCounts `json:"notes"`
+}
+
+// Related returns the notes most similar to target by TF-IDF cosine
+// similarity, best first. Term counts are cached in the vault's .noti
+// directory and only recomputed for notes that changed.
+func Related(target *notes.Note, all []*notes.Note, analyzer *Analyzer, limit int) ([]RelatedNote, error) {
+	counts, err := countTerms(all, analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inverse document frequencies over the whole vault
+	df := make(map[string]int)
+	for _, c := range counts {
+		for term := range c.Terms {
+			df[term]++
+		}
+	}
+	idf := func(term string) float64 {
+		return math.Log(float64(len(all)+1)/float64(df[term]+1)) + 1
+	}
+
+	vector := func(c *termCounts) map[string]float64 {
+		v := make(map[string]float64, len(c.Terms))
+		norm := 0.0
+		for term, n := range c.Terms {
+			w := (1 + math.Log(float64(n))) * idf(term)
+			v[term] = w
+			norm += w * w
+		}
+		norm = math.Sqrt(norm)
+		for term := range v {
+			v[term] /= norm
+		}
+		return v
+	}
+
+	targetCounts := counts[target.Slug]
+	if targetCounts == nil || len(targetCounts.Terms) == 0 {
+		return []RelatedNote{}, nil
+	}
+	targetVector := vector(targetCounts)
+
+	var related []RelatedNote
+	for _, note := range all {
+		c := counts[note.Slug]
+		if note.Slug == target.Slug || len(c.Terms) == 0 {
+			continue
+		}
+
+		v := vector(c)
+		type contribution struct {
+			term  string
+			value float64
+		}
+		var shared []contribution
+		score := 0.0
+		for term, w := range targetVector {
+			if other, ok := v[term]; ok {
+				score += w * other
+				shared = append(shared, contribution{term, w * other})
+			}
+		}
+		if score <= 0 {
+			continue
+		}
+
+		sort.Slice(shared, func(i, j int) bool {
+			if shared[i].value != shared[j].value {
+				return shared[i].value > shared[j].value
+			}
+			return shared[i].term < shared[j].term
+		})
+		terms := []string{}
+		for i := 0; i < len(shared) && i < sharedTermCount; i++ {
+			terms = append(terms, targetCounts.Words[shared[i].term])
+		}
+
+		related = append(related, RelatedNote{
+			Note:        note,
+			Score:       math.Round(score*1000) / 1000,
+			SharedTerms: terms,
+		})
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].Note.Slug < related[j].Note.Slug
+	})
+	if limit > 0 && len(related) > limit {
+		related = related[:limit]
+	}
+
+	return related, nil
+}
+
+// countTerms returns the term counts of every note by slug, using and
+// updating the cache
+func countTerms(all []*notes.Note, analyzer *Analyzer) (map[string]*termCounts, error) {
+	key := fmt.Sprintf("%s/stop=%t", analyzer.Language, analyzer.StopWords)
+
+	dir, err := config.StateDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, vectorCacheFile)
+
+	cache := loadVectorCache(path)
+	if cache.Version != vectorCacheVersion || cache.Analyzer != key {
+		cache = &vectorCache{Version: vectorCacheVersion, Analyzer: key}
+	}
+	if cache.Notes == nil {
+		cache.Notes = make(map[string]*termCounts)
+	}
+
+	changed := false
+	counts := make(map[string]*termCounts, len(all))
+	for _, note := range all {
+		text := note.Title + "\n" + note.Title + "\n" + note.Content + "\n" + strings.Join(note.AllTags(), " ")
+		modTime := note.Modified.UnixNano()
+
+		c := cache.Notes[note.Slug]
+		if c == nil || c.ModTime != modTime || c.Size != len(text) {
+			c = analyzeNote(text, analyzer)
+			c.ModTime, c.Size = modTime, len(text)
+			cache.Notes[note.Slug] = c
+			changed = true
+		}
+		counts[note.Slug] = c
+	}
+
+	// Forget deleted notes
+	for slug := range cache.Notes {
+		if _, ok := counts[slug]; !ok {
+			delete(cache.Notes, slug)
+			changed = true
+		}
+	}
+
+	if changed {
+		data, err := json.Marshal(cache)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal search vectors: %w", err)
+		}
+		if err := notes.WriteAtomic(path, data); err != nil {
+			return nil, fmt.Errorf("could not write search vectors: %w", err)
+		}
+	}
+
+	return counts, nil
+}
+
+// loadVectorCache reads the cache, returning an empty one if it is missing
+// or unreadable
+func loadVectorCache(path string) *vectorCache {
+	var cache vectorCache
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &cache) != nil {
+		return &vectorCache{}
+	}
+	return &cache
+}
+
+// analyzeNote counts the terms of text. Terms without letters, such as
+// numbers, and single letters are left out.
+func analyzeNote(text string, analyzer *Analyzer) *termCounts {
+	c := &termCounts{Terms: make(map[string]int), Words: make(map[string]string)}
+
+	for _, tok := range analyzer.Analyze(text) {
+		if len([]rune(tok.Text)) < 2 || !strings.ContainsFunc(tok.Text, unicode.IsLetter) {
+			continue
+		}
+		c.Terms[tok.Text]++
+		if _, ok := c.Words[tok.Text]; !ok {
+			c.Words[tok.Text] = lowerWord(text[tok.Start:tok.End])
+		}
+	}
+
+	return c
+}
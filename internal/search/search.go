@@ -10,94 +10,179 @@ import (
 type SearchResult struct {
 	Note    *notes.Note `json:"note"`
 	Matches []Match     `json:"matches"`
+	// More counts the matches left out by Options.MaxMatches
+	More int `json:"more,omitempty"`
 }
 
-// Match represents a single match within a note
+// Match contexts
+const (
+	ContextTitle   = "title"
+	ContextTag     = "tag"
+	ContextContent = "content"
+)
+
+// Match represents a single match within a note. Matches close together on
+// a line share one Match.
 type Match struct {
 	LineNumber int    `json:"line_number"`
 	Line       string `json:"line"`
-	Context    string `json:"context"`
+	// Context is where the match is: "title", "tag", or "content"
+	Context string `json:"context"`
+	// Section is the heading path of the line, such as
+	// "Design > API > Errors"; empty for titles, tags, and text before the
+	// first heading
+	Section string `json:"section"`
+	// Code is set for matches in fenced code blocks, and Lang is the
+	// block's language if given
+	Code bool   `json:"code"`
+	Lang string `json:"lang,omitempty"`
+	// Column is the 1-based byte offset of the first match in Line and
+	// Length its length in bytes
+	Column int `json:"column"`
+	Length int `json:"length"`
+	// Snippet is Line cut down to the matches and some context around
+	// them, and Highlights the byte ranges of the matches within it
+	Snippet    string  `json:"snippet"`
+	Highlights []Range `json:"highlights"`
 }
 
-// Search performs a full-text search across all notes
-func Search(query string) ([]*SearchResult, error) {
+// Search performs a full-text search across all notes. The query may
+// narrow the notes searched with qualifiers such as tag: and folder: (see
+// Query); a query of only qualifiers lists the matching notes. opts
+// choose how the remaining text is matched.
+func Search(query string, opts Options) ([]*SearchResult, error) {
 	// Get all notes
 	allNotes, err := notes.ListNotes("", "")
 	if err != nil {
 		return nil, err
 	}
 
+	q := ParseQuery(query)
+
+	match, err := newMatcher(q.Text, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Excluded words match by the same rules as the text
+	excludes := make([]matcher, len(q.Exclude))
+	for i, word := range q.Exclude {
+		if excludes[i], err = newMatcher(word, opts); err != nil {
+			return nil, err
+		}
+	}
+
 	var results []*SearchResult
-	queryLower := strings.ToLower(query)
 
 	for _, note := range allNotes {
-		matches := searchInNote(note, queryLower)
-		if len(matches) > 0 {
+		if !q.Matches(note) || excluded(note, excludes) {
+			continue
+		}
+
+		if q.Text == "" {
+			if q.scoped() && !inScope(note, q) {
+				continue
+			}
 			results = append(results, &SearchResult{
 				Note:    note,
-				Matches: matches,
+				Matches: []Match{},
 			})
+			continue
+		}
+
+		matches := searchInNote(note, q, match, opts.SnippetWindow)
+		if len(matches) == 0 {
+			continue
+		}
+
+		result := &SearchResult{Note: note, Matches: matches}
+		if opts.MaxMatches > 0 && len(matches) > opts.MaxMatches {
+			result.More = len(matches) - opts.MaxMatches
+			result.Matches = matches[:opts.MaxMatches]
 		}
+		results = append(results, result)
 	}
 
 	return results, nil
 }
 
 // searchInNote searches for query within a single note
-func searchInNote(note *notes.Note, queryLower string) []Match {
+func searchInNote(note *notes.Note, q Query, match matcher, window int) []Match {
+	if window <= 0 {
+		window = DefaultSnippetWindow
+	}
+
 	var matches []Match
+	add := func(line string, lineNumber int, context string, info lineInfo) {
+		for _, spans := range clusterSpans(line, match(line), window) {
+			snip, highlights := snippet(line, spans, window)
+			matches = append(matches, Match{
+				LineNumber: lineNumber,
+				Line:       line,
+				Context:    context,
+				Section:    info.Section(),
+				Code:       info.Code,
+				Lang:       info.Lang,
+				Column:     spans[0].start + 1,
+				Length:     spans[0].end - spans[0].start,
+				Snippet:    snip,
+				Highlights: highlights,
+			})
+		}
+	}
 
-	// Search in title
-	if strings.Contains(strings.ToLower(note.Title), queryLower) {
-		matches = append(matches, Match{
-			LineNumber: 0,
-			Line:       note.Title,
-			Context:    "title",
-		})
+	// Titles and tags are outside any section or code block
+	if !q.scoped() {
+		add(note.Title, 0, ContextTitle, lineInfo{})
 	}
 
 	// Search in content
 	lines := strings.Split(note.Content, "\n")
+	infos := analyzeLines(lines, note.Title)
 	for i, line := range lines {
-		if strings.Contains(strings.ToLower(line), queryLower) {
-			// Get context (line before and after if available)
-			context := getContext(lines, i)
-			matches = append(matches, Match{
-				LineNumber: i + 1,
-				Line:       line,
-				Context:    context,
-			})
+		if q.matchesLine(infos[i]) {
+			add(line, i+1, ContextContent, infos[i])
 		}
 	}
 
-	// Search in tags
-	for _, tag := range note.Tags {
-		if strings.Contains(strings.ToLower(tag), queryLower) {
-			matches = append(matches, Match{
-				LineNumber: 0,
-				Line:       tag,
-				Context:    "tag",
-			})
+	if !q.scoped() {
+		for _, tag := range note.Tags {
+			add(tag, 0, ContextTag, lineInfo{})
 		}
 	}
 
 	return matches
 }
 
-// getContext returns surrounding lines for context
-func getContext(lines []string, index int) string {
-	start := index - 1
-	if start < 0 {
-		start = 0
+// excluded reports whether any of the matchers matches the title, a line
+// of the content, or a tag of the note
+func excluded(note *notes.Note, excludes []matcher) bool {
+	if len(excludes) == 0 {
+		return false
 	}
 
-	end := index + 2
-	if end > len(lines) {
-		end = len(lines)
+	lines := append([]string{note.Title}, strings.Split(note.Content, "\n")...)
+	lines = append(lines, note.Tags...)
+	for _, match := range excludes {
+		for _, line := range lines {
+			if len(match(line)) > 0 {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	contextLines := lines[start:end]
-	return strings.Join(contextLines, " ")
+// inScope reports whether any line of the note is in the query's sections
+// and code blocks
+func inScope(note *notes.Note, q Query) bool {
+	lines := strings.Split(note.Content, "\n")
+	for _, info := range analyzeLines(lines, note.Title) {
+		if q.matchesLine(info) {
+			return true
+		}
+	}
+	return false
 }
 
 // FindByFilename searches for notes by filename pattern
@@ -111,12 +196,23 @@ func FindByFilename(pattern string) ([]*notes.Note, error) {
 	patternLower := strings.ToLower(pattern)
 
 	for _, note := range allNotes {
-		// Check if pattern matches slug or title
+		// Check if pattern matches slug, title or an alias
 		if strings.Contains(strings.ToLower(note.Slug), patternLower) ||
-			strings.Contains(strings.ToLower(note.Title), patternLower) {
+			strings.Contains(strings.ToLower(note.Title), patternLower) ||
+			aliasContains(note, patternLower) {
 			results = append(results, note)
 		}
 	}
 
 	return results, nil
 }
+
+// aliasContains reports whether any alias of note contains patternLower
+func aliasContains(note *notes.Note, patternLower string) bool {
+	for _, alias := range note.Aliases {
+		if strings.Contains(strings.ToLower(alias), patternLower) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+func TestExcluded(t *testing.T) {
+	note := &notes.Note{Title: "Plan", Content: "A redraft of the Intro", Tags: []string{"work"}}
+	english, err := NewAnalyzer(LanguageEnglish, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		word string
+		opts Options
+		want bool
+	}{
+		{"draft", Options{}, true},
+		{"draft", Options{WholeWord: true}, false},
+		{"redraft", Options{WholeWord: true}, true},
+		{"intro", Options{}, true},
+		{"intro", Options{CaseSensitive: true}, false},
+		{"Intro", Options{CaseSensitive: true}, true},
+		{"redrafting", Options{Analyzers: []*Analyzer{english}}, true},
+		{"wor", Options{WholeWord: true}, false},
+		{"work", Options{WholeWord: true}, true},
+		{"re.raft", Options{Regex: true}, true},
+		{"budget", Options{}, false},
+	}
+
+	for _, tt := range tests {
+		match, err := newMatcher(tt.word, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := excluded(note, []matcher{match}); got != tt.want {
+			t.Errorf("excluded(-%s, %+v) = %v, want %v", tt.word, tt.opts, got, tt.want)
+		}
+	}
+}
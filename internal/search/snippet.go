@@ -0,0 +1,100 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSnippetWindow is the number of characters of context shown on
+// each side of a match
+const DefaultSnippetWindow = 40
+
+// ellipsis marks text trimmed from a snippet
+const ellipsis = "…"
+
+// Range is a byte range within a snippet
+type Range struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// clusterSpans groups spans whose snippet windows would overlap, so nearby
+// matches share one snippet
+func clusterSpans(s string, spans []span, window int) [][]span {
+	var clusters [][]span
+	for _, sp := range spans {
+		if n := len(clusters); n > 0 {
+			last := clusters[n-1][len(clusters[n-1])-1]
+			if utf8.RuneCountInString(s[last.end:sp.start]) <= 2*window {
+				clusters[n-1] = append(clusters[n-1], sp)
+				continue
+			}
+		}
+		clusters = append(clusters, []span{sp})
+	}
+	return clusters
+}
+
+// snippet cuts line down to window characters around the spans, breaking
+// at spaces where possible, and returns the highlight ranges of the spans
+// within it
+func snippet(line string, spans []span, window int) (string, []Range) {
+	first, last := spans[0].start, spans[len(spans)-1].end
+
+	start := backRunes(line, first, window)
+	if start > 0 {
+		// Start at a word rather than inside one
+		if i := strings.IndexFunc(line[start:first], unicode.IsSpace); i >= 0 {
+			start += i
+		}
+	}
+	end := forwardRunes(line, last, window)
+	if end < len(line) {
+		if i := strings.LastIndexFunc(line[last:end], unicode.IsSpace); i >= 0 {
+			end = last + i
+		}
+	}
+
+	// Leading indentation and trailing space carry no information
+	for start < first && unicode.IsSpace(rune(line[start])) {
+		start++
+	}
+	for end > last && unicode.IsSpace(rune(line[end-1])) {
+		end--
+	}
+
+	var b strings.Builder
+	if strings.TrimSpace(line[:start]) != "" {
+		b.WriteString(ellipsis)
+	}
+	offset := b.Len() - start
+	b.WriteString(line[start:end])
+	if strings.TrimSpace(line[end:]) != "" {
+		b.WriteString(ellipsis)
+	}
+
+	ranges := make([]Range, len(spans))
+	for i, sp := range spans {
+		ranges[i] = Range{Start: sp.start + offset, End: sp.end + offset}
+	}
+	return b.String(), ranges
+}
+
+// backRunes moves back n runes from byte offset i
+func backRunes(s string, i, n int) int {
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+	}
+	return i
+}
+
+// forwardRunes moves forward n runes from byte offset i
+func forwardRunes(s string, i, n int) int {
+	for ; n > 0 && i < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return i
+}
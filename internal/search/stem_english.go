@@ -0,0 +1,258 @@
+package search
+
+import "strings"
+
+// stemEnglish reduces an English word to its stem with the Porter
+// algorithm, so "running", "runs", and "run" share the stem "run". Words
+// that are not plain ASCII letters are returned unchanged.
+func stemEnglish(word string) string {
+	// Possessives: "note's" and "notes'" stem like "note" and "notes"
+	word = strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "'")
+
+	if len(word) <= 2 || !isASCIILetters(word) {
+		return word
+	}
+
+	p := &porter{b: []byte(word)}
+	p.step1a()
+	p.step1b()
+	p.step1c()
+	p.step2()
+	p.step3()
+	p.step4()
+	p.step5()
+	return string(p.b)
+}
+
+func isASCIILetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// porter holds a word being stemmed
+type porter struct {
+	b []byte
+}
+
+// consonant reports whether b[i] is a consonant; y is a consonant unless it
+// follows one
+func (p *porter) consonant(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !p.consonant(i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in b[:n], the m in
+// [C](VC){m}[V]
+func (p *porter) measure(n int) int {
+	m, i := 0, 0
+	for i < n && p.consonant(i) {
+		i++
+	}
+	for i < n {
+		for i < n && !p.consonant(i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		for i < n && p.consonant(i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+// hasVowel reports whether b[:n] contains a vowel
+func (p *porter) hasVowel(n int) bool {
+	for i := 0; i < n; i++ {
+		if !p.consonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleConsonant reports whether b[:n] ends in a double consonant
+func (p *porter) doubleConsonant(n int) bool {
+	return n >= 2 && p.b[n-1] == p.b[n-2] && p.consonant(n-1)
+}
+
+// cvc reports whether b[:n] ends consonant-vowel-consonant where the last
+// consonant is not w, x, or y, as in "hop" but not "snow"
+func (p *porter) cvc(n int) bool {
+	if n < 3 || !p.consonant(n-1) || p.consonant(n-2) || !p.consonant(n-3) {
+		return false
+	}
+	switch p.b[n-1] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+func (p *porter) ends(suffix string) bool {
+	return strings.HasSuffix(string(p.b), suffix)
+}
+
+// stem is the length of the word without suffix
+func (p *porter) stem(suffix string) int {
+	return len(p.b) - len(suffix)
+}
+
+func (p *porter) setTo(suffix, replacement string) {
+	p.b = append(p.b[:p.stem(suffix)], replacement...)
+}
+
+// replace swaps suffix for replacement when the stem's measure exceeds min
+func (p *porter) replace(suffix, replacement string, min int) bool {
+	if !p.ends(suffix) {
+		return false
+	}
+	if p.measure(p.stem(suffix)) > min {
+		p.setTo(suffix, replacement)
+	}
+	return true
+}
+
+// step1a removes plurals: caresses -> caress, ponies -> poni, cats -> cat
+func (p *porter) step1a() {
+	switch {
+	case p.ends("sses"):
+		p.setTo("sses", "ss")
+	case p.ends("ies"):
+		p.setTo("ies", "i")
+	case p.ends("ss"):
+	case p.ends("s"):
+		p.setTo("s", "")
+	}
+}
+
+// step1b removes -ed and -ing: agreed -> agree, hopping -> hop,
+// filing -> file
+func (p *porter) step1b() {
+	if p.ends("eed") {
+		if p.measure(p.stem("eed")) > 0 {
+			p.setTo("eed", "ee")
+		}
+		return
+	}
+
+	removed := false
+	for _, suffix := range []string{"ed", "ing"} {
+		if p.ends(suffix) && p.hasVowel(p.stem(suffix)) {
+			p.setTo(suffix, "")
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		return
+	}
+
+	n := len(p.b)
+	switch {
+	case p.ends("at"), p.ends("bl"), p.ends("iz"):
+		p.b = append(p.b, 'e')
+	case p.doubleConsonant(n):
+		switch p.b[n-1] {
+		case 'l', 's', 'z':
+		default:
+			p.b = p.b[:n-1]
+		}
+	case p.measure(n) == 1 && p.cvc(n):
+		p.b = append(p.b, 'e')
+	}
+}
+
+// step1c turns a final y into i after a vowel: happy -> happi
+func (p *porter) step1c() {
+	if p.ends("y") && p.hasVowel(p.stem("y")) {
+		p.b[len(p.b)-1] = 'i'
+	}
+}
+
+var porterStep2 = [][2]string{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	{"logi", "log"},
+}
+
+// step2 maps double suffixes to single ones: relational -> relate
+func (p *porter) step2() {
+	for _, r := range porterStep2 {
+		if p.replace(r[0], r[1], 0) {
+			return
+		}
+	}
+}
+
+var porterStep3 = [][2]string{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// step3 removes -ful, -ness, and similar: hopeful -> hope
+func (p *porter) step3() {
+	for _, r := range porterStep3 {
+		if p.replace(r[0], r[1], 0) {
+			return
+		}
+	}
+}
+
+var porterStep4 = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+// step4 removes suffixes from long stems: adjustable -> adjust
+func (p *porter) step4() {
+	// Longest suffix first, as "ement" must win over "ment" and "ent"
+	best := ""
+	for _, suffix := range porterStep4 {
+		if p.ends(suffix) && len(suffix) > len(best) {
+			best = suffix
+		}
+	}
+	if best == "" {
+		return
+	}
+
+	n := p.stem(best)
+	if best == "ion" && (n == 0 || (p.b[n-1] != 's' && p.b[n-1] != 't')) {
+		return
+	}
+	if p.measure(n) > 1 {
+		p.b = p.b[:n]
+	}
+}
+
+// step5 removes a final e and simplifies a final ll: probate -> probat,
+// controll -> control
+func (p *porter) step5() {
+	n := len(p.b)
+	if p.b[n-1] == 'e' {
+		m := p.measure(n - 1)
+		if m > 1 || (m == 1 && !p.cvc(n-1)) {
+			p.b = p.b[:n-1]
+		}
+	}
+
+	n = len(p.b)
+	if n > 1 && p.b[n-1] == 'l' && p.doubleConsonant(n) && p.measure(n) > 1 {
+		p.b = p.b[:n-1]
+	}
+}
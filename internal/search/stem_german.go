@@ -0,0 +1,242 @@
+package search
+
+import "strings"
+
+// stemGerman reduces a German word to its stem with the Snowball German
+// algorithm, so "Häuser" and "Haus" share the stem "haus". Like the
+// german2 variant, "ae", "oe", and "ue" are read as umlauts, so notes
+// written without them still match.
+func stemGerman(word string) string {
+	runes := []rune(germanUmlauts(strings.ReplaceAll(word, "ß", "ss")))
+	if len(runes) <= 2 {
+		return word
+	}
+
+	// Mark u and y between vowels as consonants
+	for i := 1; i < len(runes)-1; i++ {
+		if germanVowel(runes[i-1]) && germanVowel(runes[i+1]) {
+			switch runes[i] {
+			case 'u':
+				runes[i] = 'U'
+			case 'y':
+				runes[i] = 'Y'
+			}
+		}
+	}
+
+	r1, r2 := germanRegions(runes)
+	g := &german{w: runes, r1: r1, r2: r2}
+	g.step1()
+	g.step2()
+	g.step3()
+
+	var b strings.Builder
+	for _, r := range g.w {
+		switch r {
+		case 'U':
+			r = 'u'
+		case 'Y':
+			r = 'y'
+		case 'ä':
+			r = 'a'
+		case 'ö':
+			r = 'o'
+		case 'ü':
+			r = 'u'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// germanUmlauts replaces ae, oe, and ue with umlauts. A ue after q or a
+// vowel is left alone, as in "Quelle" or "neue".
+func germanUmlauts(s string) string {
+	if !strings.Contains(s, "e") {
+		return s
+	}
+
+	runes := []rune(s)
+	out := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if i+1 < len(runes) && runes[i+1] == 'e' {
+			umlaut := rune(0)
+			switch r {
+			case 'a':
+				umlaut = 'ä'
+			case 'o':
+				umlaut = 'ö'
+			case 'u':
+				if i == 0 || (runes[i-1] != 'q' && !germanVowel(runes[i-1])) {
+					umlaut = 'ü'
+				}
+			}
+			if umlaut != 0 {
+				out = append(out, umlaut)
+				i++
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+func germanVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y', 'ä', 'ö', 'ü':
+		return true
+	}
+	return false
+}
+
+// germanRegions returns the start of R1 and R2: R1 follows the first
+// consonant after a vowel, but leaves at least three letters before it; R2
+// is the same region found again within R1
+func germanRegions(w []rune) (int, int) {
+	region := func(from int) int {
+		for i := from + 1; i < len(w); i++ {
+			if !germanVowel(w[i]) && germanVowel(w[i-1]) {
+				return i + 1
+			}
+		}
+		return len(w)
+	}
+
+	r1 := region(0)
+	if r1 < 3 {
+		r1 = 3
+	}
+	if r1 > len(w) {
+		r1 = len(w)
+	}
+	r2 := region(r1)
+	if r2 < r1 {
+		r2 = r1
+	}
+	return r1, r2
+}
+
+// german holds a word being stemmed
+type german struct {
+	w      []rune
+	r1, r2 int
+}
+
+func (g *german) ends(suffix string) bool {
+	return strings.HasSuffix(string(g.w), suffix)
+}
+
+// start is the position of suffix in the word
+func (g *german) start(suffix string) int {
+	return len(g.w) - len([]rune(suffix))
+}
+
+// longest returns the longest of suffixes the word ends with
+func (g *german) longest(suffixes ...string) string {
+	best := ""
+	for _, suffix := range suffixes {
+		if g.ends(suffix) && len(suffix) > len(best) {
+			best = suffix
+		}
+	}
+	return best
+}
+
+func (g *german) cut(suffix string) {
+	g.w = g.w[:g.start(suffix)]
+}
+
+// validEnding reports whether the rune before position i is one of endings
+func (g *german) validEnding(i int, endings string) bool {
+	return i > 0 && strings.ContainsRune(endings, g.w[i-1])
+}
+
+// step1 removes inflectional endings: -em, -ern, -er, -e, -en, -es, -s
+func (g *german) step1() {
+	suffix := g.longest("em", "ern", "er", "e", "en", "es", "s")
+	if suffix == "" {
+		return
+	}
+	i := g.start(suffix)
+	if i < g.r1 {
+		return
+	}
+
+	switch suffix {
+	case "s":
+		if g.validEnding(i, "bdfghklmnrt") {
+			g.cut(suffix)
+		}
+	case "e", "en", "es":
+		g.cut(suffix)
+		if g.ends("niss") {
+			g.w = g.w[:len(g.w)-1]
+		}
+	default:
+		g.cut(suffix)
+	}
+}
+
+// step2 removes -en, -er, -est, and -st
+func (g *german) step2() {
+	suffix := g.longest("en", "er", "est", "st")
+	if suffix == "" {
+		return
+	}
+	i := g.start(suffix)
+	if i < g.r1 {
+		return
+	}
+
+	if suffix == "st" {
+		// st must follow a valid ending that has at least three letters
+		// before it
+		if i >= 4 && g.validEnding(i, "bdfghklmnt") {
+			g.cut(suffix)
+		}
+		return
+	}
+	g.cut(suffix)
+}
+
+// step3 removes derivational suffixes such as -ung, -heit, and -lich
+func (g *german) step3() {
+	suffix := g.longest("end", "ung", "ig", "ik", "isch", "lich", "heit", "keit")
+	if suffix == "" {
+		return
+	}
+	i := g.start(suffix)
+	if i < g.r2 {
+		return
+	}
+
+	switch suffix {
+	case "end", "ung":
+		g.cut(suffix)
+		if g.ends("ig") && g.start("ig") >= g.r2 && !g.ends("eig") {
+			g.cut("ig")
+		}
+	case "ig", "ik", "isch":
+		if !g.validEnding(i, "e") {
+			g.cut(suffix)
+		}
+	case "lich", "heit":
+		g.cut(suffix)
+		for _, prefix := range []string{"er", "en"} {
+			if g.ends(prefix) && g.start(prefix) >= g.r1 {
+				g.cut(prefix)
+				break
+			}
+		}
+	case "keit":
+		g.cut(suffix)
+		for _, prefix := range []string{"lich", "ig"} {
+			if g.ends(prefix) && g.start(prefix) >= g.r2 {
+				g.cut(prefix)
+				break
+			}
+		}
+	}
+}
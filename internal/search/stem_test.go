@@ -0,0 +1,136 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+// The expected stems are taken from the reference vocabularies of the
+// Porter and Snowball German algorithms
+
+func TestStemEnglish(t *testing.T) {
+	tests := map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"ties":            "ti",
+		"cats":            "cat",
+		"feed":            "feed",
+		"agreed":          "agre",
+		"plastered":       "plaster",
+		"bled":            "bled",
+		"motoring":        "motor",
+		"sing":            "sing",
+		"conflated":       "conflat",
+		"troubled":        "troubl",
+		"sized":           "size",
+		"hopping":         "hop",
+		"falling":         "fall",
+		"hissing":         "hiss",
+		"fizzed":          "fizz",
+		"filing":          "file",
+		"happy":           "happi",
+		"sky":             "sky",
+		"deploy":          "deploi",
+		"relational":      "relat",
+		"conditional":     "condit",
+		"rational":        "ration",
+		"generalizations": "gener",
+		"oscillators":     "oscil",
+		"electrical":      "electr",
+		"hopeful":         "hope",
+		"goodness":        "good",
+		"replacement":     "replac",
+		"adjustment":      "adjust",
+		"effective":       "effect",
+		"probate":         "probat",
+		"rate":            "rate",
+		"cease":           "ceas",
+		"controll":        "control",
+		"roll":            "roll",
+		"is":              "is",
+	}
+
+	for word, want := range tests {
+		if got := stemEnglish(word); got != want {
+			t.Errorf("stemEnglish(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestStemGerman(t *testing.T) {
+	tests := map[string]string{
+		"aufeinanderfolgenden": "aufeinanderfolg",
+		"häuser":               "haus",
+		"häusern":              "haus",
+		"haeuser":              "haus",
+		"kategorien":           "kategori",
+		"aufgaben":             "aufgab",
+		"abgeschlossen":        "abgeschloss",
+		"möglichkeiten":        "moglich",
+		"gelaufen":             "gelauf",
+		"kinder":               "kind",
+		"zeitungen":            "zeitung",
+		"straße":               "strass",
+		"strasse":              "strass",
+		"quelle":               "quell",
+		"neue":                 "neu",
+		"zu":                   "zu",
+	}
+
+	for word, want := range tests {
+		if got := stemGerman(word); got != want {
+			t.Errorf("stemGerman(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := map[string]string{
+		"Don't stop, e.g. now":  "don't stop e.g now",
+		"pi is 3.14, not 1,000": "pi is 3.14 not 1,000",
+		"it’s ΟΔΟΣ":             "it's οδοσ",
+		"東京タワーへ":                "東 京 タワー へ",
+		"end. Next":             "end next",
+	}
+
+	for input, want := range tests {
+		var words []string
+		for _, tok := range Tokenize(input) {
+			if tok.Start < 0 || tok.End > len(input) || tok.Start >= tok.End {
+				t.Errorf("Tokenize(%q) has token %q at invalid offsets %d-%d", input, tok.Text, tok.Start, tok.End)
+			}
+			words = append(words, tok.Text)
+		}
+		if got := strings.Join(words, " "); got != want {
+			t.Errorf("Tokenize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestAnalyzerTerms(t *testing.T) {
+	tests := []struct {
+		language  string
+		stopWords bool
+		input     string
+		want      string
+	}{
+		{LanguageEnglish, true, "The connections are running", "connect run"},
+		{LanguageEnglish, false, "The café", "the cafe"},
+		{LanguageGerman, true, "Die Häuser und das Haus", "haus haus"},
+		{LanguageNone, true, "The Running Café", "the running cafe"},
+	}
+
+	for _, tt := range tests {
+		a, err := NewAnalyzer(tt.language, tt.stopWords)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(a.Terms(tt.input), " "); got != tt.want {
+			t.Errorf("%s Terms(%q) = %q, want %q", tt.language, tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := NewAnalyzer("klingon", false); err == nil {
+		t.Error("NewAnalyzer() with an unknown language succeeded, want an error")
+	}
+}
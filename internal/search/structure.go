@@ -0,0 +1,107 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+)
+
+// sectionSeparator joins the headings of a section path
+const sectionSeparator = " > "
+
+var atxHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+
+// lineInfo describes where a line of note content sits
+type lineInfo struct {
+	// Headings is the path of headings the line is under, outermost first
+	Headings []string
+	// Code is set for lines of fenced code blocks, fences included, and
+	// Lang is the block's language if given
+	Code bool
+	Lang string
+}
+
+// Section returns the heading path as "Design > API > Errors"
+func (l lineInfo) Section() string {
+	return strings.Join(l.Headings, sectionSeparator)
+}
+
+// analyzeLines returns the heading path and code block of each line of
+// content. A heading line belongs to its own section. Headings in fenced
+// code blocks are ignored, and a top-level heading repeating the note
+// title is left out of the paths.
+func analyzeLines(lines []string, title string) []lineInfo {
+	infos := make([]lineInfo, len(lines))
+
+	type heading struct {
+		level int
+		text  string
+	}
+	var stack []heading
+	fence, lang := "", ""
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			infos[i].Code, infos[i].Lang = true, lang
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		} else if marker := notes.FenceMarker(trimmed); marker != "" {
+			fence = marker
+			lang = ""
+			if fields := strings.Fields(trimmed[len(marker):]); len(fields) > 0 {
+				lang = strings.ToLower(strings.Trim(fields[0], "{}."))
+			}
+			infos[i].Code, infos[i].Lang = true, lang
+		} else if m := atxHeadingPattern.FindStringSubmatch(line); m != nil {
+			level, text := len(m[1]), strings.TrimSpace(m[2])
+			for len(stack) > 0 && stack[len(stack)-1].level >= level {
+				stack = stack[:len(stack)-1]
+			}
+			if !(level == 1 && strings.EqualFold(text, strings.TrimSpace(title))) && text != "" {
+				stack = append(stack, heading{level, text})
+			}
+		}
+
+		if len(stack) > 0 {
+			path := make([]string, len(stack))
+			for j, h := range stack {
+				path[j] = h.text
+			}
+			infos[i].Headings = path
+		}
+	}
+
+	return infos
+}
+
+// inSection reports whether headings contain the section, given as one
+// heading ("Errors") or a path ("API > Errors"), ignoring case
+func inSection(headings []string, section string) bool {
+	var want []string
+	for _, part := range strings.Split(section, ">") {
+		if part = strings.TrimSpace(part); part != "" {
+			want = append(want, part)
+		}
+	}
+	if len(want) == 0 {
+		return false
+	}
+
+	for i := 0; i+len(want) <= len(headings); i++ {
+		matched := true
+		for j, w := range want {
+			if !strings.EqualFold(headings[i+j], w) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a word of text. Text is the normalized term; Start and End are
+// the byte offsets of the word in the original text.
+type Token struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// Tokenize splits s into lowercased words following the Unicode word
+// boundary rules for common scripts: apostrophes and periods inside words
+// ("don't", "e.g") and separators inside numbers ("3.14", "1,000") do not
+// break a word, combining marks stay with their letter, and each Han or
+// Hiragana character is a word of its own.
+func Tokenize(s string) []Token {
+	var tokens []Token
+	start, prev := -1, rune(0)
+
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, Token{Text: lowerWord(s[start:end]), Start: start, End: end})
+			start = -1
+		}
+	}
+
+	for i, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r):
+			flush(i)
+			end := i + utf8.RuneLen(r)
+			tokens = append(tokens, Token{Text: string(r), Start: i, End: end})
+
+		case isKatakana(r):
+			if start >= 0 && !isKatakana(prev) {
+				flush(i)
+			}
+			if start < 0 {
+				start = i
+			}
+
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || (unicode.IsMark(r) && start >= 0):
+			if start >= 0 && isKatakana(prev) {
+				flush(i)
+			}
+			if start < 0 {
+				start = i
+			}
+
+		case start >= 0 && joinsWord(prev, r, s[i+utf8.RuneLen(r):]):
+			// Part of the word; the next rune decides
+
+		default:
+			flush(i)
+		}
+		prev = r
+	}
+	flush(len(s))
+
+	return tokens
+}
+
+// isKatakana reports whether r is katakana or the prolonged sound mark,
+// which join into one word
+func isKatakana(r rune) bool {
+	return unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
+
+// joinsWord reports whether the separator r, between prev and the text
+// after it, is inside a word
+func joinsWord(prev, r rune, after string) bool {
+	next, _ := utf8.DecodeRuneInString(after)
+
+	switch r {
+	case '\'', '’', '·':
+		return unicode.IsLetter(prev) && unicode.IsLetter(next)
+	case '.':
+		return (unicode.IsLetter(prev) && unicode.IsLetter(next)) || (unicode.IsDigit(prev) && unicode.IsDigit(next))
+	case ',':
+		return unicode.IsDigit(prev) && unicode.IsDigit(next)
+	}
+	return false
+}
+
+// lowerWord case-folds a word, normalizing typographic apostrophes
+func lowerWord(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(s, "’", "'"))
+	// Final sigma folds to sigma
+	return strings.ReplaceAll(s, "ς", "σ")
+}
+
+// Languages supported for stemming and stop words
+const (
+	LanguageEnglish = "english"
+	LanguageGerman  = "german"
+	// LanguageNone only folds case and diacritics
+	LanguageNone = "none"
+)
+
+// Analyzer turns text into search terms: it tokenizes, drops stop words,
+// stems, and folds diacritics, so "Running" and "run" or "Häuser" and
+// "haus" produce the same term
+type Analyzer struct {
+	Language  string
+	StopWords bool
+}
+
+// NewAnalyzer returns an analyzer for a language
+func NewAnalyzer(language string, stopWords bool) (*Analyzer, error) {
+	switch language {
+	case LanguageEnglish, LanguageGerman, LanguageNone:
+		return &Analyzer{Language: language, StopWords: stopWords}, nil
+	default:
+		return nil, fmt.Errorf("unknown search language %q (use %s, %s, or %s)", language, LanguageEnglish, LanguageGerman, LanguageNone)
+	}
+}
+
+// NewAnalyzers returns an analyzer for each language, defaulting to English
+func NewAnalyzers(languages []string, stopWords bool) ([]*Analyzer, error) {
+	if len(languages) == 0 {
+		languages = []string{LanguageEnglish}
+	}
+
+	analyzers := make([]*Analyzer, 0, len(languages))
+	for _, language := range languages {
+		a, err := NewAnalyzer(strings.ToLower(language), stopWords)
+		if err != nil {
+			return nil, err
+		}
+		analyzers = append(analyzers, a)
+	}
+	return analyzers, nil
+}
+
+// Analyze returns the terms of s with their positions in s
+func (a *Analyzer) Analyze(s string) []Token {
+	tokens := Tokenize(s)
+
+	out := tokens[:0]
+	for _, tok := range tokens {
+		if a.StopWords && a.isStopWord(tok.Text) {
+			continue
+		}
+		tok.Text = foldDiacritics(a.stem(tok.Text))
+		if tok.Text != "" {
+			out = append(out, tok)
+		}
+	}
+	return out
+}
+
+// Terms returns the terms of s
+func (a *Analyzer) Terms(s string) []string {
+	tokens := a.Analyze(s)
+	terms := make([]string, len(tokens))
+	for i, tok := range tokens {
+		terms[i] = tok.Text
+	}
+	return terms
+}
+
+func (a *Analyzer) isStopWord(word string) bool {
+	switch a.Language {
+	case LanguageEnglish:
+		return englishStopWords[word]
+	case LanguageGerman:
+		return germanStopWords[word]
+	}
+	return false
+}
+
+func (a *Analyzer) stem(word string) string {
+	switch a.Language {
+	case LanguageEnglish:
+		return stemEnglish(word)
+	case LanguageGerman:
+		return stemGerman(word)
+	}
+	return word
+}
+
+// foldDiacritics strips accents from letters: "café" becomes "cafe"
+func foldDiacritics(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if folded, ok := diacritics[r]; ok {
+			b.WriteString(folded)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// diacritics maps lowercase accented letters to their base letters
+var diacritics = func() map[rune]string {
+	groups := map[string]string{
+		"àáâãäåāăą":  "a",
+		"çćĉċč":      "c",
+		"ďđ":         "d",
+		"èéêëēĕėęě":  "e",
+		"ĝğġģ":       "g",
+		"ĥħ":         "h",
+		"ìíîïĩīĭįı":  "i",
+		"ĵ":          "j",
+		"ķ":          "k",
+		"ĺļľŀł":      "l",
+		"ñńņňŉ":      "n",
+		"òóôõöøōŏő":  "o",
+		"ŕŗř":        "r",
+		"śŝşšș":      "s",
+		"ţťŧț":       "t",
+		"ùúûüũūŭůűų": "u",
+		"ŵ":          "w",
+		"ýÿŷ":        "y",
+		"źżž":        "z",
+		"ά":          "α",
+		"έ":          "ε",
+		"ή":          "η",
+		"ίϊΐ":        "ι",
+		"ό":          "ο",
+		"ύϋΰ":        "υ",
+		"ώ":          "ω",
+		"й":          "и",
+		"ё":          "е",
+	}
+
+	m := map[rune]string{'ß': "ss", 'æ': "ae", 'œ': "oe", 'þ': "th", 'ð': "d"}
+	for letters, base := range groups {
+		for _, r := range letters {
+			m[r] = base
+		}
+	}
+	return m
+}()
@@ -0,0 +1,238 @@
+package tags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/notes"
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+)
+
+// Change is the rewrite of a single note
+type Change struct {
+	// Path is the note path relative to the notes directory
+	Path   string
+	Before []byte
+	After  []byte
+	// Tags is the note's new tag list
+	Tags []string
+}
+
+// Slug returns the slug of the changed note
+func (c Change) Slug() string {
+	return strings.TrimSuffix(c.Path, ".md")
+}
+
+// Transform maps a note's tag list to a new one
+type Transform func(tags []string) []string
+
+// Rename replaces tag old with new. Nested tags move along, so
+// project/alpha becomes new/alpha.
+func Rename(old, new string) Transform {
+	return Merge([]string{old}, new)
+}
+
+// Merge replaces every tag in sources, and the tags nested below them, with
+// into: merging project into work turns project/alpha into work/alpha
+func Merge(sources []string, into string) Transform {
+	return func(tags []string) []string {
+		out := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			for _, source := range sources {
+				if tag == source {
+					tag = into
+					break
+				}
+				if strings.HasPrefix(tag, source+"/") {
+					tag = into + tag[len(source):]
+					break
+				}
+			}
+			out = append(out, tag)
+		}
+		return dedupe(out)
+	}
+}
+
+// CheckMerge reports an error if into is one of sources or nested below
+// one, which would make Merge nest the tag below itself
+func CheckMerge(sources []string, into string) error {
+	for _, source := range sources {
+		if strings.HasPrefix(into, source+"/") {
+			return fmt.Errorf("cannot move tag %s below itself to %s", source, into)
+		}
+	}
+	return nil
+}
+
+// Remove drops tag. Tags nested below it are kept.
+func Remove(tag string) Transform {
+	return func(tags []string) []string {
+		out := make([]string, 0, len(tags))
+		for _, t := range tags {
+			if t != tag {
+				out = append(out, t)
+			}
+		}
+		return out
+	}
+}
+
+// Edit changes a note's frontmatter in place and returns its new content,
+// reporting whether anything changed
+type Edit func(fm *frontmatter.Frontmatter, content string) (string, bool)
+
+// Plan applies transform to the tags of every document and returns the
+// notes that would change
+func Plan(docs []*notes.Document, transform Transform) ([]Change, error) {
+	return PlanEdit(docs, Retag(transform))
+}
+
+// Retag turns a tag transform into an edit of the frontmatter tags and of
+// the inline #hashtags in the content
+func Retag(transform Transform) Edit {
+	return func(fm *frontmatter.Frontmatter, content string) (string, bool) {
+		changed := false
+		if tags := transform(fm.Tags); !equal(tags, fm.Tags) {
+			fm.Tags = tags
+			changed = true
+		}
+
+		// Transforms work tag by tag, so each hashtag maps to what the
+		// transform makes of it alone: nothing when it is removed
+		rewrite := make(map[string]string)
+		for _, tag := range notes.InlineTags(content) {
+			switch out := transform([]string{tag}); {
+			case len(out) == 0:
+				rewrite[tag] = ""
+			case out[0] != tag:
+				rewrite[tag] = out[0]
+			}
+		}
+		if len(rewrite) > 0 {
+			content = notes.RewriteInlineTags(content, rewrite)
+			changed = true
+		}
+
+		return content, changed
+	}
+}
+
+// Promote adds inline #hashtags to the frontmatter tags. With strip, the
+// hashtags are also removed from the content.
+func Promote(strip bool) Edit {
+	return func(fm *frontmatter.Frontmatter, content string) (string, bool) {
+		inline := notes.InlineTags(content)
+		if len(inline) == 0 {
+			return content, false
+		}
+
+		changed := false
+		present := make(map[string]bool)
+		for _, tag := range fm.Tags {
+			present[tag] = true
+		}
+		for _, tag := range inline {
+			if !present[tag] {
+				fm.Tags = append(fm.Tags, tag)
+				present[tag] = true
+				changed = true
+			}
+		}
+
+		if strip {
+			remove := make(map[string]bool)
+			for _, tag := range inline {
+				remove[tag] = true
+			}
+			content = notes.StripInlineTags(content, remove)
+			changed = true
+		}
+
+		return content, changed
+	}
+}
+
+// PlanEdit applies edit to every document and returns the notes that
+// would change. Documents whose frontmatter cannot be parsed are left
+// alone; in documents without frontmatter only the content is edited.
+func PlanEdit(docs []*notes.Document, edit Edit) ([]Change, error) {
+	var changes []Change
+
+	for _, doc := range docs {
+		if doc.ParseErr != nil {
+			continue
+		}
+		if doc.Frontmatter == nil {
+			var fm frontmatter.Frontmatter
+			content, changed := edit(&fm, doc.Content)
+			// Edits that add tags would need a frontmatter block
+			if changed && len(fm.Tags) == 0 && content != doc.Content {
+				changes = append(changes, Change{
+					Path:   doc.Path,
+					Before: doc.Data,
+					After:  []byte(content),
+					Tags:   []string{},
+				})
+			}
+			continue
+		}
+
+		fm := *doc.Frontmatter
+		fm.Tags = append([]string(nil), doc.Frontmatter.Tags...)
+		if fm.Tags == nil && doc.Frontmatter.Tags != nil {
+			fm.Tags = []string{}
+		}
+
+		content, changed := edit(&fm, doc.Content)
+		if !changed {
+			continue
+		}
+
+		updated := *doc
+		updated.Frontmatter = &fm
+		updated.Content = content
+
+		data, err := updated.Render()
+		if err != nil {
+			return nil, fmt.Errorf("could not format %s: %w", doc.Path, err)
+		}
+		if string(data) == string(doc.Data) {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Path:   doc.Path,
+			Before: doc.Data,
+			After:  data,
+			Tags:   fm.Tags,
+		})
+	}
+
+	return changes, nil
+}
+
+// dedupe removes repeated tags, keeping the first occurrence
+func dedupe(tags []string) []string {
+	seen := make(map[string]bool)
+	out := tags[:0]
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package tags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devjasha/noti-vim/pkg/frontmatter"
+)
+
+func TestTransforms(t *testing.T) {
+	input := []string{"project", "project/alpha", "projects", "work", "todo", "later/soon"}
+
+	tests := []struct {
+		name      string
+		transform Transform
+		want      string
+	}{
+		{"rename", Rename("project", "work"), "work work/alpha projects todo later/soon"},
+		{"rename nested", Rename("project/alpha", "alpha"), "project alpha projects work todo later/soon"},
+		{"merge", Merge([]string{"todo", "later"}, "backlog"), "project project/alpha projects work backlog backlog/soon"},
+		{"remove", Remove("project"), "project/alpha projects work todo later/soon"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(tt.transform(input), " "); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if err := CheckMerge([]string{"project"}, "project/old"); err == nil {
+		t.Error("CheckMerge() of a tag into its own child succeeded, want an error")
+	}
+	if err := CheckMerge([]string{"project"}, "projects/old"); err != nil {
+		t.Errorf("CheckMerge() = %v, want nil", err)
+	}
+}
+
+func TestRetagRenamesInlineSubtree(t *testing.T) {
+	fm := &frontmatter.Frontmatter{Tags: []string{"project/alpha"}}
+	content, changed := Retag(Rename("project", "work"))(fm, "Notes on #project/alpha and #project, not #projects.")
+
+	if !changed || strings.Join(fm.Tags, " ") != "work/alpha" {
+		t.Errorf("Retag() tags = %v, changed %v, want [work/alpha]", fm.Tags, changed)
+	}
+	if want := "Notes on #work/alpha and #work, not #projects."; content != want {
+		t.Errorf("Retag() content = %q, want %q", content, want)
+	}
+}
@@ -0,0 +1,147 @@
+package vocab
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devjasha/noti-vim/internal/config"
+)
+
+// Source names where the vocabulary is defined, for messages
+const Source = "the vocabulary in " + config.VaultFile
+
+// Vocabulary is the set of tags a vault agrees to use. Tags are matched
+// case-insensitively; synonyms map to their canonical tag.
+type Vocabulary struct {
+	// Strict rejects tags that are not in the vocabulary instead of warning
+	Strict bool
+	Tags   map[string]config.TagEntry
+
+	lookup map[string]string
+}
+
+// Load reads the vocabulary from the vault configuration. Without one, the
+// vocabulary is empty and accepts every tag unchanged.
+func Load() (*Vocabulary, error) {
+	vault, err := config.LoadVault()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(vault.Vocabulary)
+}
+
+// New builds a vocabulary from its configuration
+func New(cfg config.VocabularyConfig) (*Vocabulary, error) {
+	v := &Vocabulary{Strict: cfg.Strict, Tags: cfg.Tags}
+	if err := v.index(); err != nil {
+		return nil, fmt.Errorf("invalid vocabulary in %s: %w", config.VaultFile, err)
+	}
+
+	return v, nil
+}
+
+// index builds the lookup table, rejecting names claimed by two tags
+func (v *Vocabulary) index() error {
+	v.lookup = make(map[string]string)
+
+	canonical := make([]string, 0, len(v.Tags))
+	for tag := range v.Tags {
+		canonical = append(canonical, tag)
+	}
+	sort.Strings(canonical)
+
+	add := func(name, tag string) error {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if other, ok := v.lookup[key]; ok && other != tag {
+			return fmt.Errorf("%q is used by both %q and %q", name, other, tag)
+		}
+		v.lookup[key] = tag
+		return nil
+	}
+
+	for _, tag := range canonical {
+		if err := add(tag, tag); err != nil {
+			return err
+		}
+	}
+	for _, tag := range canonical {
+		for _, synonym := range v.Tags[tag].Synonyms {
+			if err := add(synonym, tag); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Empty reports whether the vocabulary defines no tags
+func (v *Vocabulary) Empty() bool {
+	return len(v.Tags) == 0
+}
+
+// Canonical returns the canonical form of tag and whether it is known.
+// A nested tag is known if one of its parents is, and its first matching
+// parent is rewritten: with "kubernetes" having the synonym "k8s",
+// "K8s/networking" becomes "kubernetes/networking".
+func (v *Vocabulary) Canonical(tag string) (string, bool) {
+	if v.Empty() {
+		return tag, true
+	}
+
+	key := strings.ToLower(strings.TrimSpace(tag))
+	if canonical, ok := v.lookup[key]; ok {
+		return canonical, true
+	}
+
+	parts := strings.Split(tag, "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		parent := strings.ToLower(strings.Join(parts[:i], "/"))
+		if canonical, ok := v.lookup[parent]; ok {
+			return canonical + "/" + strings.Join(parts[i:], "/"), true
+		}
+	}
+
+	return tag, false
+}
+
+// Normalize maps tags to their canonical forms, dropping duplicates, and
+// returns the tags that are not in the vocabulary
+func (v *Vocabulary) Normalize(tags []string) (normalized []string, unknown []string) {
+	seen := make(map[string]bool)
+	normalized = make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		canonical, ok := v.Canonical(tag)
+		if !ok {
+			unknown = append(unknown, tag)
+		}
+		if !seen[canonical] {
+			seen[canonical] = true
+			normalized = append(normalized, canonical)
+		}
+	}
+
+	return normalized, unknown
+}
+
+// Check normalizes tags and, in strict mode, fails if any are unknown.
+// Outside strict mode the unknown tags are returned for a warning.
+func (v *Vocabulary) Check(tags []string) ([]string, []string, error) {
+	normalized, unknown := v.Normalize(tags)
+	if v.Strict && len(unknown) > 0 {
+		return nil, unknown, fmt.Errorf("unknown tag(s) %s (add them to %s or use a listed tag)", strings.Join(unknown, ", "), Source)
+	}
+	return normalized, unknown, nil
+}
+
+// Description returns the description of tag's canonical form
+func (v *Vocabulary) Description(tag string) string {
+	canonical, ok := v.Canonical(tag)
+	if !ok {
+		return ""
+	}
+	return v.Tags[canonical].Description
+}
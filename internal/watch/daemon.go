@@ -0,0 +1,205 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devjasha/noti-vim/internal/git"
+)
+
+// Options configures the watch daemon
+type Options struct {
+	// Root is the directory to watch
+	Root string
+	// Debounce is the quiet period after the last change before committing
+	Debounce time.Duration
+	// MaxDelay caps how long changes wait for a quiet period, so a steady
+	// stream of writes is still committed; zero waits indefinitely
+	MaxDelay time.Duration
+	// SyncInterval is how often to pull and push; zero disables syncing
+	SyncInterval time.Duration
+	// Poll forces the polling watcher instead of the native one
+	Poll bool
+	// PollInterval is the scan interval of the polling watcher
+	PollInterval time.Duration
+	// Logger receives daemon activity
+	Logger *log.Logger
+}
+
+// Run watches the notes directory, commits after each quiet period and
+// syncs with the remote on an interval until ctx is cancelled
+func Run(ctx context.Context, opts Options) error {
+	if !git.IsGitRepo() {
+		return fmt.Errorf("not a git repository (use 'noti git init' to initialize)")
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	w, err := NewWatcher(opts.Root, opts.Poll, opts.PollInterval)
+	if err != nil {
+		return fmt.Errorf("could not start watcher: %w", err)
+	}
+	defer w.Close()
+
+	mode := "native"
+	if _, ok := w.(*pollWatcher); ok {
+		mode = "polling"
+	}
+	logger.Printf("watching %s (%s, debounce %s, max delay %s, sync every %s)", opts.Root, mode, opts.Debounce, opts.MaxDelay, opts.SyncInterval)
+
+	// Timer that fires once the directory has been quiet for the debounce period
+	quiet := time.NewTimer(opts.Debounce)
+	if !quiet.Stop() {
+		<-quiet.C
+	}
+
+	var syncTick <-chan time.Time
+	if opts.SyncInterval > 0 {
+		ticker := time.NewTicker(opts.SyncInterval)
+		defer ticker.Stop()
+		syncTick = ticker.C
+	}
+
+	pending := make(map[string]bool)
+	// firstChange is when the oldest pending change arrived
+	var firstChange time.Time
+	errs := w.Errors()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if len(pending) > 0 {
+				commitPending(logger, opts.Root, pending)
+			}
+			logger.Printf("stopped")
+			return nil
+
+		case path, ok := <-w.Events():
+			if !ok {
+				return fmt.Errorf("watcher stopped unexpectedly")
+			}
+			if len(pending) == 0 {
+				firstChange = time.Now()
+			}
+			pending[path] = true
+			// Drain a tick that fired but was not received yet, or it would
+			// commit in the middle of a burst of edits
+			if !quiet.Stop() {
+				select {
+				case <-quiet.C:
+				default:
+				}
+			}
+			quiet.Reset(commitDelay(opts, firstChange))
+
+		case err, ok := <-errs:
+			if !ok {
+				// Stop selecting a closed channel
+				errs = nil
+				continue
+			}
+			logger.Printf("watch error: %v", err)
+
+		case <-quiet.C:
+			commitPending(logger, opts.Root, pending)
+			pending = make(map[string]bool)
+
+		case <-syncTick:
+			syncRemote(logger)
+		}
+	}
+}
+
+// commitDelay returns how long to wait for more changes before committing:
+// the debounce period, cut short once the oldest change has waited
+// MaxDelay
+func commitDelay(opts Options, firstChange time.Time) time.Duration {
+	delay := opts.Debounce
+	if opts.MaxDelay > 0 {
+		if left := opts.MaxDelay - time.Since(firstChange); left < delay {
+			delay = left
+		}
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// commitPending commits the working tree if there is anything to commit
+func commitPending(logger *log.Logger, root string, pending map[string]bool) {
+	status, err := git.Status()
+	if err != nil {
+		logger.Printf("could not get status: %v", err)
+		return
+	}
+
+	if strings.TrimSpace(status) == "" {
+		return
+	}
+
+	message := commitMessage(root, pending)
+	if err := git.Commit(message); err != nil {
+		logger.Printf("commit failed: %v", err)
+		return
+	}
+
+	logger.Printf("committed: %s", message)
+}
+
+// commitMessage summarizes the changed paths for an automatic commit
+func commitMessage(root string, pending map[string]bool) string {
+	var names []string
+	for path := range pending {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			continue
+		}
+		if rel, err := filepath.Rel(root, path); err == nil {
+			names = append(names, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(names)
+
+	switch {
+	case len(names) == 0:
+		return "Auto-commit notes"
+	case len(names) <= 3:
+		return "Auto-commit: " + strings.Join(names, ", ")
+	default:
+		return fmt.Sprintf("Auto-commit: %s and %d more", strings.Join(names[:3], ", "), len(names)-3)
+	}
+}
+
+// syncRemote pulls and pushes if a remote is configured
+func syncRemote(logger *log.Logger) {
+	hasRemote, err := git.HasRemote()
+	if err != nil {
+		logger.Printf("could not check remotes: %v", err)
+		return
+	}
+
+	if !hasRemote {
+		return
+	}
+
+	if err := git.Pull(); err != nil {
+		logger.Printf("pull failed: %v", err)
+		return
+	}
+
+	if err := git.Push(); err != nil {
+		logger.Printf("push failed: %v", err)
+		return
+	}
+
+	logger.Printf("synced with remote")
+}
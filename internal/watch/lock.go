@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// AcquireLock creates a PID file at path so only one watcher runs per vault.
+// A stale lock left behind by a process that no longer exists is replaced.
+// The returned function removes the lock.
+func AcquireLock(path string) (func(), error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, werr := fmt.Fprintf(f, "%d\n", os.Getpid())
+			cerr := f.Close()
+			if werr != nil || cerr != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("could not write lock file: %v", firstErr(werr, cerr))
+			}
+			return func() { os.Remove(path) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("could not create lock file: %w", err)
+		}
+
+		pid, err := ReadLock(path)
+		if err == nil && processAlive(pid) {
+			return nil, fmt.Errorf("noti watch is already running (pid %d)", pid)
+		}
+
+		// Stale lock, remove it and try again
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("could not remove stale lock file: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("could not acquire lock file %s", path)
+}
+
+// ReadLock returns the PID stored in a lock file
+func ReadLock(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid lock file: %w", err)
+	}
+
+	return pid, nil
+}
+
+// Running returns the PID of the live watcher holding the lock at path
+func Running(path string) (int, bool) {
+	pid, err := ReadLock(path)
+	if err != nil || !processAlive(pid) {
+		return 0, false
+	}
+	return pid, true
+}
+
+// Stop terminates the watcher holding the lock at path
+func Stop(path string) (int, error) {
+	pid, err := ReadLock(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("noti watch is not running")
+		}
+		return 0, err
+	}
+
+	if !processAlive(pid) {
+		os.Remove(path)
+		return 0, fmt.Errorf("noti watch is not running (removed stale lock)")
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return 0, fmt.Errorf("could not find process %d: %w", pid, err)
+	}
+
+	if err := terminate(proc); err != nil {
+		return 0, fmt.Errorf("could not stop process %d: %w", pid, err)
+	}
+
+	return pid, nil
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
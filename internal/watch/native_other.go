@@ -0,0 +1,9 @@
+//go:build !linux
+
+package watch
+
+import "fmt"
+
+func newNativeWatcher(root string) (Watcher, error) {
+	return nil, fmt.Errorf("native file watching is not supported on this platform")
+}
@@ -0,0 +1,128 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// pollWatcher detects changes by periodically walking the directory tree
+type pollWatcher struct {
+	root     string
+	interval time.Duration
+	events   chan string
+	errors   chan error
+	done     chan struct{}
+}
+
+func newPollWatcher(root string, interval time.Duration) (*pollWatcher, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	w := &pollWatcher{
+		root:     root,
+		interval: interval,
+		events:   make(chan string),
+		errors:   make(chan error),
+		done:     make(chan struct{}),
+	}
+
+	state, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	go w.loop(state)
+	return w, nil
+}
+
+func (w *pollWatcher) Events() <-chan string { return w.events }
+
+func (w *pollWatcher) Errors() <-chan error { return w.errors }
+
+func (w *pollWatcher) Close() error {
+	close(w.done)
+	return nil
+}
+
+func (w *pollWatcher) loop(state map[string]fileState) {
+	defer close(w.events)
+	defer close(w.errors)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		next, err := w.scan()
+		if err != nil {
+			select {
+			case w.errors <- err:
+			case <-w.done:
+				return
+			}
+			continue
+		}
+
+		var changed []string
+		for path, st := range next {
+			if prev, ok := state[path]; !ok || !prev.modTime.Equal(st.modTime) || prev.size != st.size {
+				changed = append(changed, path)
+			}
+		}
+		for path := range state {
+			if _, ok := next[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+		state = next
+
+		for _, path := range changed {
+			select {
+			case w.events <- path:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// scan records the modification time and size of every watched file
+func (w *pollWatcher) scan() (map[string]fileState, error) {
+	state := make(map[string]fileState)
+
+	err := filepath.Walk(w.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Files can disappear between listing and stat
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if ignored(w.root, path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.IsDir() {
+			state[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+
+	return state, err
+}
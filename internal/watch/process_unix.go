@@ -0,0 +1,29 @@
+//go:build !windows
+
+package watch
+
+import (
+	"os"
+	"syscall"
+)
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
+}
+
+func terminate(proc *os.Process) error {
+	return proc.Signal(syscall.SIGTERM)
+}
+
+// DetachAttr returns process attributes that start a child in its own
+// session so it outlives the terminal that launched it
+func DetachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
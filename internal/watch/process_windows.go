@@ -0,0 +1,29 @@
+//go:build windows
+
+package watch
+
+import (
+	"os"
+	"syscall"
+)
+
+// processAlive reports whether a process with the given PID exists.
+// On Windows FindProcess opens a handle and fails for unknown PIDs.
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	proc.Release()
+	return true
+}
+
+func terminate(proc *os.Process) error {
+	return proc.Kill()
+}
+
+// DetachAttr returns process attributes that start a child in its own
+// process group so it outlives the console that launched it
+func DetachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
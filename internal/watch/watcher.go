@@ -0,0 +1,48 @@
+package watch
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Watcher reports paths that change below a root directory
+type Watcher interface {
+	// Events returns a channel of changed file paths
+	Events() <-chan string
+	// Errors returns a channel of non-fatal watcher errors
+	Errors() <-chan error
+	// Close stops the watcher and closes its channels
+	Close() error
+}
+
+// NewWatcher returns the native watcher for the platform, or a polling
+// watcher if poll is set or no native implementation is available
+func NewWatcher(root string, poll bool, interval time.Duration) (Watcher, error) {
+	if !poll {
+		w, err := newNativeWatcher(root)
+		if err == nil {
+			return w, nil
+		}
+	}
+
+	return newPollWatcher(root, interval)
+}
+
+// ignored reports whether a path below root should not trigger a sync.
+// Hidden files and directories (.git, .noti, editor swap files) and
+// backup files are skipped.
+func ignored(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+
+	return strings.HasSuffix(path, "~")
+}
@@ -0,0 +1,193 @@
+package frontmatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseValue parses a command-line value as YAML, so "true" is a bool,
+// "2024-03-01" a date, "[a, b]" a list, and anything else a string
+func ParseValue(s string) (interface{}, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("could not parse value %q: %w", s, err)
+	}
+	if v == nil {
+		return s, nil
+	}
+	return v, nil
+}
+
+// Keys returns the names of the fields that are set, built-in fields first
+func (fm *Frontmatter) Keys() []string {
+	var keys []string
+	for _, key := range []string{"id", "title", "tags", "created", "aliases"} {
+		if _, ok := fm.Get(key); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var extra []string
+	for key := range fm.Extra {
+		extra = append(extra, key)
+	}
+	sort.Strings(extra)
+
+	return append(keys, extra...)
+}
+
+// Get returns the value of a field and whether it is set
+func (fm *Frontmatter) Get(key string) (interface{}, bool) {
+	switch key {
+	case "id":
+		return fm.ID, fm.ID != ""
+	case "title":
+		return fm.Title, fm.Title != ""
+	case "tags":
+		return fm.Tags, fm.Tags != nil
+	case "created":
+		return fm.Created, !fm.Created.IsZero()
+	case "aliases":
+		return fm.Aliases, fm.Aliases != nil
+	}
+
+	v, ok := fm.Extra[key]
+	return v, ok
+}
+
+// Set sets a field. Values for built-in fields are converted to the
+// field's type; a single value given for tags or aliases becomes a list.
+func (fm *Frontmatter) Set(key string, value interface{}) error {
+	switch key {
+	case "id":
+		fm.ID = scalarString(value)
+	case "title":
+		fm.Title = scalarString(value)
+	case "tags", "aliases":
+		list, err := StringList(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if key == "tags" {
+			fm.Tags = list
+		} else {
+			fm.Aliases = list
+		}
+	case "created":
+		t, err := toTime(value)
+		if err != nil {
+			return fmt.Errorf("created: %w", err)
+		}
+		fm.Created = t
+	default:
+		if fm.Extra == nil {
+			fm.Extra = make(map[string]interface{})
+		}
+		fm.Extra[key] = value
+	}
+
+	return nil
+}
+
+// Unset removes a field
+func (fm *Frontmatter) Unset(key string) {
+	switch key {
+	case "id":
+		fm.ID = ""
+	case "title":
+		fm.Title = ""
+	case "tags":
+		fm.Tags = []string{}
+	case "created":
+		fm.Created = time.Time{}
+	case "aliases":
+		fm.Aliases = nil
+	default:
+		delete(fm.Extra, key)
+	}
+}
+
+// StringList converts a list or a single value to a list of strings
+func StringList(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return []string{}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				return nil, fmt.Errorf("list items must be plain values")
+			}
+			list = append(list, scalarString(item))
+		}
+		return list, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("expected a list, got a mapping")
+	default:
+		return []string{scalarString(v)}, nil
+	}
+}
+
+// scalarString formats a plain value as a string
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.Equal(v.Truncate(24*time.Hour)) && v.Location() == time.UTC {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%v is not a date", value)
+}
+
+// FormatValue formats a field value for display: lists as comma-separated
+// items and mappings as YAML
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, FormatValue(item))
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return strings.TrimSpace(string(data))
+	default:
+		return scalarString(v)
+	}
+}
@@ -0,0 +1,68 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetParsedDate(t *testing.T) {
+	fm := &Frontmatter{Title: "T"}
+
+	due, err := ParseValue("2024-04-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Set("due", due); err != nil {
+		t.Fatal(err)
+	}
+	at, err := ParseValue("2024-04-02T10:15:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Set("at", at); err != nil {
+		t.Fatal(err)
+	}
+	dates, err := ParseValue("[2024-05-01, 2024-06-01]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Set("reviews", dates); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Format(fm, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"\ndue: 2024-04-02\n", "\nat: 2024-04-02T10:15:00Z\n", "\n    - 2024-05-01\n    - 2024-06-01\n"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Format() =\n%s\nwant it to contain %q", out, want)
+		}
+	}
+
+	// The value is still a date when read back
+	parsed, _, err := Parse(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	due, _ = parsed.Get("due")
+	if _, ok := due.(time.Time); !ok || FormatValue(due) != "2024-04-02" {
+		t.Errorf("Get(due) = %#v, want the date 2024-04-02", due)
+	}
+}
+
+func TestSetCreatedKeepsTimestamp(t *testing.T) {
+	fm := &Frontmatter{Title: "T"}
+	if err := fm.Set("created", "2024-04-02T00:00:00Z"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Format(fm, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "\ncreated: 2024-04-02T00:00:00Z\n") {
+		t.Errorf("Format() =\n%s\nwant the full created timestamp", out)
+	}
+}
@@ -3,6 +3,8 @@ package frontmatter
 import (
 	"bytes"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,9 +13,180 @@ import (
 
 // Frontmatter represents the YAML frontmatter of a markdown note
 type Frontmatter struct {
+	// ID is an optional stable identifier independent of title and path
+	ID      string    `yaml:"id,omitempty"`
 	Title   string    `yaml:"title"`
 	Tags    []string  `yaml:"tags"`
 	Created time.Time `yaml:"created"`
+	// Aliases are other names the note can be linked and looked up by
+	Aliases []string `yaml:"aliases,omitempty"`
+
+	// Extra holds any other fields so they survive a Parse/Format round trip
+	Extra map[string]interface{} `yaml:"-"`
+
+	// raw holds the parsed Extra fields in file order. Fields whose value is
+	// unchanged are written back from their nodes, so their formatting,
+	// comments and types (such as dates) are kept.
+	raw []rawField
+	// builtin records which built-in fields the parsed source had, so empty
+	// ones are written back only if they were there
+	builtin map[string]bool
+}
+
+type rawField struct {
+	key, value *yaml.Node
+	// parsed is the value as decoded, to tell whether Extra changed it
+	parsed interface{}
+}
+
+// UnmarshalYAML decodes the built-in fields and keeps the nodes of all
+// others alongside their values in Extra
+func (fm *Frontmatter) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: frontmatter must be a mapping", node.Line)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		var err error
+		switch key.Value {
+		case "id", "title", "tags", "created", "aliases":
+			if fm.builtin == nil {
+				fm.builtin = make(map[string]bool)
+			}
+			fm.builtin[key.Value] = true
+		}
+
+		switch key.Value {
+		case "id":
+			err = value.Decode(&fm.ID)
+		case "title":
+			err = value.Decode(&fm.Title)
+		case "tags":
+			err = value.Decode(&fm.Tags)
+		case "created":
+			err = value.Decode(&fm.Created)
+		case "aliases":
+			err = value.Decode(&fm.Aliases)
+		default:
+			var v, parsed interface{}
+			if err = value.Decode(&v); err != nil {
+				break
+			}
+			if err = value.Decode(&parsed); err != nil {
+				break
+			}
+			if fm.Extra == nil {
+				fm.Extra = make(map[string]interface{})
+			}
+			fm.Extra[key.Value] = v
+			fm.raw = append(fm.raw, rawField{key: key, value: value, parsed: parsed})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalYAML writes the built-in fields first, then the other fields in
+// the order they were parsed, then new fields sorted by name. An empty
+// title or tags field is left out unless the parsed source had it, and a
+// zero created time is always left out, so editing one field of a note
+// does not add others it never had. Tags set to an empty list are written.
+func (fm Frontmatter) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value interface{}) error {
+		v, err := encodeValue(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+		return nil
+	}
+
+	if fm.ID != "" {
+		if err := add("id", fm.ID); err != nil {
+			return nil, err
+		}
+	}
+	if fm.Title != "" || fm.builtin["title"] {
+		if err := add("title", fm.Title); err != nil {
+			return nil, err
+		}
+	}
+	if fm.Tags != nil || fm.builtin["tags"] {
+		if err := add("tags", fm.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if !fm.Created.IsZero() {
+		// created keeps its full timestamp, even at midnight
+		created := &yaml.Node{}
+		if err := created.Encode(fm.Created); err != nil {
+			return nil, fmt.Errorf("created: %w", err)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "created"}, created)
+	}
+	if len(fm.Aliases) > 0 {
+		if err := add("aliases", fm.Aliases); err != nil {
+			return nil, err
+		}
+	}
+
+	written := make(map[string]bool)
+	for _, field := range fm.raw {
+		value, ok := fm.Extra[field.key.Value]
+		if !ok || written[field.key.Value] {
+			continue
+		}
+		written[field.key.Value] = true
+
+		if reflect.DeepEqual(value, field.parsed) {
+			node.Content = append(node.Content, field.key, field.value)
+		} else if err := add(field.key.Value, value); err != nil {
+			return nil, err
+		}
+	}
+
+	var keys []string
+	for key := range fm.Extra {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := add(key, fm.Extra[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// encodeValue encodes a field value, writing times at midnight UTC, such
+// as those parsed from "2024-03-01", as plain dates
+func encodeValue(value interface{}) (*yaml.Node, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+
+	var dates func(n *yaml.Node)
+	dates = func(n *yaml.Node) {
+		if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!timestamp" && strings.HasSuffix(n.Value, "T00:00:00Z") {
+			n.Value = strings.TrimSuffix(n.Value, "T00:00:00Z")
+		}
+		for _, child := range n.Content {
+			dates(child)
+		}
+	}
+	dates(node)
+
+	return node, nil
 }
 
 // Parse parses a markdown file with YAML frontmatter
@@ -50,6 +223,10 @@ func Format(fm *Frontmatter, content string) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal frontmatter: %w", err)
 	}
+	if bytes.Equal(fmData, []byte("{}\n")) {
+		// A frontmatter without fields is an empty block
+		fmData = nil
+	}
 
 	// Build complete file
 	var buf bytes.Buffer
@@ -60,3 +237,25 @@ func Format(fm *Frontmatter, content string) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// ContentLine returns the 1-based line number in data at which the content
+// returned by Parse begins
+func ContentLine(data []byte) int {
+	if !bytes.HasPrefix(data, []byte("---\n")) && !bytes.HasPrefix(data, []byte("---\r\n")) {
+		return 1
+	}
+
+	delimiter := []byte("---")
+	parts := bytes.SplitN(data, delimiter, 3)
+	if len(parts) < 3 {
+		return 1
+	}
+
+	// Frontmatter, both delimiters, and the whitespace trimmed from content
+	prefixLen := len(parts[0]) + len(parts[1]) + 2*len(delimiter)
+	body := parts[2]
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	prefixLen += len(body) - len(trimmed)
+
+	return bytes.Count(data[:prefixLen], []byte("\n")) + 1
+}
@@ -0,0 +1,148 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatKeepsUnknownFields(t *testing.T) {
+	input := `---
+title: Trip
+tags:
+    - travel
+created: 2024-01-01T09:30:00Z
+due: 2024-03-01 # before spring
+rating: 0x1F
+price: 1.50
+confirmed: "yes"
+hotel:
+    name: Central
+    nights: [1, 2]
+---
+
+Packing list
+`
+
+	fm, content, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Format(fm, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), strings.TrimSuffix(input, "\n"); got != want {
+		t.Errorf("Format() changed the note:\n%s\nwant\n%s", got, want)
+	}
+
+	// Editing one field leaves the others as they were
+	fm.Tags = append(fm.Tags, "spring")
+	if err := fm.Set("rating", 5); err != nil {
+		t.Fatal(err)
+	}
+	fm.Unset("confirmed")
+	if err := fm.Set("budget", 900); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = Format(fm, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `---
+title: Trip
+tags:
+    - travel
+    - spring
+created: 2024-01-01T09:30:00Z
+due: 2024-03-01 # before spring
+rating: 5
+price: 1.50
+hotel:
+    name: Central
+    nights: [1, 2]
+budget: 900
+---
+
+Packing list`
+	if string(out) != want {
+		t.Errorf("Format() after edits =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestParseKeepsValuesOfUnknownFields(t *testing.T) {
+	fm, _, err := Parse([]byte("---\ntitle: T\ncount: 3\nlist: [a, b]\n---\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := fm.Get("count"); !ok || v != 3 {
+		t.Errorf("Get(count) = %v, %v, want 3", v, ok)
+	}
+	if v, _ := fm.Get("list"); FormatValue(v) != "a, b" {
+		t.Errorf("Get(list) = %v, want [a b]", v)
+	}
+	if _, _, err := Parse([]byte("---\ntitle: [a]\n---\n")); err == nil {
+		t.Error("Parse() with a list title succeeded, want an error")
+	}
+}
+
+func TestFormatAddsNoBuiltinFields(t *testing.T) {
+	// A note without created gains only the field that was set
+	fm, content, err := Parse([]byte("---\ntitle: A\n---\n\nBody\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Set("reviewed", true); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Format(fm, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\ntitle: A\nreviewed: true\n---\n\nBody"; string(out) != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", out, want)
+	}
+
+	// Empty fields the source had are kept
+	fm, content, err = Parse([]byte("---\ntitle: \"\"\ntags: []\n---\n\nBody\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = Format(fm, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\ntitle: \"\"\ntags: []\n---\n\nBody"; string(out) != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestFormatPlainMarkdown(t *testing.T) {
+	fm, content, err := Parse([]byte("# Plain\n\nBody\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm != nil {
+		t.Fatalf("Parse() of plain markdown = %+v, want no frontmatter", fm)
+	}
+
+	fm = &Frontmatter{}
+	out, err := Format(fm, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\n---\n\n# Plain\n\nBody\n"; string(out) != want {
+		t.Errorf("Format() of an empty frontmatter =\n%s\nwant\n%s", out, want)
+	}
+
+	fm.Tags = append(fm.Tags, "newtag")
+	out, err = Format(fm, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\ntags:\n    - newtag\n---\n\n# Plain\n\nBody\n"; string(out) != want {
+		t.Errorf("Format() after adding a tag =\n%s\nwant\n%s", out, want)
+	}
+}
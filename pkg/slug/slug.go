@@ -0,0 +1,161 @@
+package slug
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Strategy selects how the slug of a new note is built
+type Strategy string
+
+const (
+	// StrategyTitle uses the title only: "meeting-notes"
+	StrategyTitle Strategy = "title"
+	// StrategyDate prefixes the title with the date: "2024-03-01-meeting-notes"
+	StrategyDate Strategy = "date"
+	// StrategyZettel prefixes the title with a timestamp ID: "20240301143000-meeting-notes"
+	StrategyZettel Strategy = "zettel"
+)
+
+// DefaultMaxLength is the slug length limit used when none is configured
+const DefaultMaxLength = 80
+
+// Options configures slug generation
+type Options struct {
+	Strategy Strategy
+	// MaxLength limits the slug length in characters; 0 uses DefaultMaxLength
+	MaxLength int
+	// Now is the time used for date and zettel prefixes; zero means time.Now
+	Now time.Time
+}
+
+// ParseStrategy validates a strategy name. An empty name is the title strategy.
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(name) {
+	case "", StrategyTitle:
+		return StrategyTitle, nil
+	case StrategyDate, StrategyZettel:
+		return Strategy(name), nil
+	default:
+		return "", fmt.Errorf("unknown slug strategy %q (use %q, %q or %q)", name, StrategyTitle, StrategyDate, StrategyZettel)
+	}
+}
+
+// Make converts a title into a slug: transliterated to ASCII where a table
+// exists, lowercased, with runs of other characters collapsed into single
+// hyphens. Letters from scripts without a table are kept as they are. The
+// result is never empty.
+func Make(title string) string {
+	var b strings.Builder
+	hyphen := false
+
+	for _, part := range transliterate(title) {
+		for _, r := range part {
+			switch {
+			case r == '\'' || r == '’':
+				// Drop apostrophes so "don't" becomes "dont"
+				continue
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				if hyphen && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				hyphen = false
+				b.WriteRune(r)
+			default:
+				hyphen = true
+			}
+		}
+	}
+
+	if b.Len() == 0 {
+		return fallback(title)
+	}
+
+	return b.String()
+}
+
+// Generate builds the slug for a new note according to opts
+func Generate(title string, opts Options) string {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	maxLength := opts.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLength
+	}
+
+	prefix := ""
+	switch opts.Strategy {
+	case StrategyDate:
+		prefix = now.Format("2006-01-02") + "-"
+	case StrategyZettel:
+		prefix = now.Format("20060102150405") + "-"
+	}
+
+	limit := maxLength - len(prefix)
+	if limit < 1 {
+		limit = 1
+	}
+
+	return prefix + Truncate(Make(title), limit)
+}
+
+// Truncate shortens a slug to at most max characters, cutting at a hyphen
+// when that keeps most of the slug
+func Truncate(slug string, max int) string {
+	if max < 1 {
+		max = 1
+	}
+
+	runes := []rune(slug)
+	if len(runes) <= max {
+		return slug
+	}
+
+	cut := string(runes[:max])
+	if i := strings.LastIndex(cut, "-"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+
+	return strings.Trim(cut, "-")
+}
+
+// Unique returns slug, or slug with the lowest free -N suffix (starting at
+// -2) if exists reports it as taken
+func Unique(slug string, exists func(string) bool) string {
+	if !exists(slug) {
+		return slug
+	}
+
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", slug, n)
+		if !exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+var prefixPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}|\d{14})-`)
+
+// TitlePart strips a date or zettel prefix from a slug, leaving the part
+// derived from the title
+func TitlePart(slug string) string {
+	return prefixPattern.ReplaceAllString(slug, "")
+}
+
+// fallback returns a stable slug for titles with no usable characters
+func fallback(title string) string {
+	if strings.TrimSpace(title) == "" {
+		return "untitled"
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(title))
+	return fmt.Sprintf("note-%08x", h.Sum32())
+}
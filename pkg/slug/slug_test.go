@@ -0,0 +1,106 @@
+package slug
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMake(t *testing.T) {
+	tests := map[string]string{
+		"Meeting Notes":      "meeting-notes",
+		"  Don't   panic! ":  "dont-panic",
+		"Café crème brûlée":  "cafe-creme-brulee",
+		"Größe & Übung":      "groesse-uebung",
+		"Привет мир":         "privet-mir",
+		"Καλημέρα κόσμε":     "kalimera-kosme",
+		"さくら":                "sakura",
+		"きょうと":               "kyouto",
+		"ちょっと":               "chotto",
+		"コーヒー":               "kohi",
+		"日本語のノート":            "日本語のノート",
+		"東京 タワー":             "東京-タワー",
+		"Go 言語 notes":        "go-言語-notes",
+		"中文笔记":               "中文笔记",
+		"2024: Q1/Q2 review": "2024-q1-q2-review",
+		"":                   "untitled",
+		"   ":                "untitled",
+		"!!!":                "note-2d53a722",
+	}
+
+	for title, want := range tests {
+		if got := Make(title); got != want {
+			t.Errorf("Make(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{Options{Strategy: StrategyTitle, Now: now}, "meeting-notes-for-march"},
+		{Options{Strategy: StrategyDate, Now: now}, "2024-03-01-meeting-notes-for-march"},
+		{Options{Strategy: StrategyZettel, Now: now}, "20240301143000-meeting-notes-for-march"},
+		{Options{Strategy: StrategyTitle, MaxLength: 16, Now: now}, "meeting-notes"},
+		{Options{Strategy: StrategyDate, MaxLength: 20, Now: now}, "2024-03-01-meeting"},
+	}
+
+	for _, tt := range tests {
+		if got := Generate("Meeting notes for March", tt.opts); got != tt.want {
+			t.Errorf("Generate(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestTitlePart(t *testing.T) {
+	tests := map[string]string{
+		"2024-03-01-meeting":     "meeting",
+		"20240301143000-meeting": "meeting",
+		"2024-meeting":           "2024-meeting",
+		"meeting":                "meeting",
+	}
+
+	for slug, want := range tests {
+		if got := TitlePart(slug); got != want {
+			t.Errorf("TitlePart(%q) = %q, want %q", slug, got, want)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		slug  string
+		taken []string
+		want  string
+	}{
+		{"notes", nil, "notes"},
+		{"notes", []string{"notes"}, "notes-2"},
+		{"notes", []string{"notes", "notes-2", "notes-3"}, "notes-4"},
+		{"notes", []string{"notes", "notes-3"}, "notes-2"},
+		{"notes-2", []string{"notes-2"}, "notes-2-2"},
+	}
+
+	for _, tt := range tests {
+		taken := map[string]bool{}
+		for _, s := range tt.taken {
+			taken[s] = true
+		}
+		exists := func(s string) bool { return taken[s] }
+		if got := Unique(tt.slug, exists); got != tt.want {
+			t.Errorf("Unique(%q) with %v taken = %q, want %q", tt.slug, tt.taken, got, tt.want)
+		}
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	for name, want := range map[string]Strategy{"": StrategyTitle, "title": StrategyTitle, "date": StrategyDate, "zettel": StrategyZettel} {
+		if got, err := ParseStrategy(name); err != nil || got != want {
+			t.Errorf("ParseStrategy(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseStrategy("random"); err == nil {
+		t.Error("ParseStrategy() with an unknown name succeeded, want an error")
+	}
+}
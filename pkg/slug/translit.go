@@ -0,0 +1,167 @@
+package slug
+
+import "unicode"
+
+// latin maps accented Latin letters to ASCII. German umlauts and ß use
+// their conventional two-letter spellings.
+var latin = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'ä': "ae", 'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e", 'ĕ': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i", 'ĭ': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ņ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'ŏ': "o",
+	'ö': "oe", 'œ': "oe",
+	'ŕ': "r", 'ř': "r", 'ŗ': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ŝ': "s",
+	'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t",
+	'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ů': "u", 'ū': "u", 'ű': "u", 'ų': "u", 'ŭ': "u",
+	'ü': "ue",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// cyrillic follows common Russian and Ukrainian romanization
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u", 'ј': "j",
+	'љ': "lj", 'њ': "nj", 'ћ': "c", 'ђ': "dj", 'џ': "dz",
+}
+
+var greek = map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o",
+	'ϊ': "i", 'ϋ': "y", 'ΐ': "i", 'ΰ': "y",
+}
+
+// kana maps hiragana to Hepburn romaji. Katakana is converted to hiragana
+// before lookup.
+var kana = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n", 'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+}
+
+// smallY maps the small ya/yu/yo kana to the vowel they combine into
+var smallY = map[rune]string{'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo"}
+
+const (
+	smallTsu  = 'っ'
+	longVowel = 'ー'
+)
+
+// transliterate lowercases s and returns the ASCII spelling of each rune
+// that has one, or the rune itself otherwise. Kana are kept as they are
+// when s contains kanji, which have no table, so Japanese titles are not
+// half romanized.
+func transliterate(s string) []string {
+	romaji := !hasKanji(s)
+	runes := []rune(s)
+	for i, r := range runes {
+		r = unicode.ToLower(r)
+		// Katakana to hiragana
+		if romaji && r >= 'ァ' && r <= 'ヶ' {
+			r -= 0x60
+		}
+		runes[i] = r
+	}
+
+	out := make([]string, 0, len(runes))
+	double := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if !romaji && (unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == longVowel) {
+			out = append(out, string(r))
+			continue
+		}
+
+		if r == smallTsu {
+			double = true
+			continue
+		}
+		if r == longVowel {
+			continue
+		}
+
+		if roma, ok := kana[r]; ok {
+			// Combine with a following small ya/yu/yo: き + ゃ = kya, し + ゃ = sha
+			if i+1 < len(runes) {
+				if y, ok := smallY[runes[i+1]]; ok && len(roma) > 1 {
+					stem := roma[:len(roma)-1]
+					if stem == "sh" || stem == "ch" || stem == "j" {
+						roma = stem + y[1:]
+					} else {
+						roma = stem + y
+					}
+					i++
+				}
+			}
+			if double {
+				roma = roma[:1] + roma
+				double = false
+			}
+			out = append(out, roma)
+			continue
+		}
+		double = false
+
+		if y, ok := smallY[r]; ok {
+			out = append(out, y)
+			continue
+		}
+
+		if ascii, ok := latin[r]; ok {
+			out = append(out, ascii)
+		} else if ascii, ok := cyrillic[r]; ok {
+			out = append(out, ascii)
+		} else if ascii, ok := greek[r]; ok {
+			out = append(out, ascii)
+		} else {
+			out = append(out, string(r))
+		}
+	}
+
+	return out
+}
+
+// hasKanji reports whether s contains any Han character
+func hasKanji(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
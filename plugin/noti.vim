@@ -12,6 +12,7 @@ let g:noti_notes_dir = get(g:, 'noti_notes_dir', expand('~/notes'))
 let g:noti_default_folder = get(g:, 'noti_default_folder', '')
 let g:noti_default_tags = get(g:, 'noti_default_tags', [])
 let g:noti_git_auto_commit = get(g:, 'noti_git_auto_commit', 0)
+let g:noti_complete = get(g:, 'noti_complete', 1)
 
 " Check if noti CLI is available
 function! s:CheckNotiCLI()
@@ -148,16 +149,45 @@ function! noti#Search(query)
     let l:query = a:query
   endif
 
-  let l:output = system('noti search "' . l:query . '" --json')
+  let l:output = system('noti search ' . shellescape(l:query) . ' --json')
   if v:shell_error != 0
     echoerr 'Search failed: ' . l:output
     return
   endif
 
-  let l:results = json_decode(l:output)
+  call s:ShowSearchResults('Search Results for: "' . l:query . '"', json_decode(l:output), 'No matches found for: ' . l:query)
+endfunction
+
+" Run a saved search
+function! noti#SavedSearch(name)
+  if !s:CheckNotiCLI()
+    return
+  endif
+
+  let l:output = system('noti saved run ' . shellescape(a:name) . ' --json')
+  if v:shell_error != 0
+    echoerr 'Saved search failed: ' . l:output
+    return
+  endif
+
+  call s:ShowSearchResults('Saved search "' . a:name . '"', json_decode(l:output), 'No matches found for saved search: ' . a:name)
+endfunction
+
+" Complete saved search names
+function! s:CompleteSaved(arglead, cmdline, cursorpos)
+  let l:names = systemlist('noti saved list --quiet')
+  if v:shell_error != 0
+    return []
+  endif
+  return filter(l:names, 'stridx(v:val, a:arglead) == 0')
+endfunction
+
+" Show search results in a new buffer
+function! s:ShowSearchResults(title, results, empty_message)
+  let l:results = a:results
 
   if empty(l:results)
-    echo 'No matches found for: ' . l:query
+    echo a:empty_message
     return
   endif
 
@@ -170,7 +200,7 @@ function! noti#Search(query)
   setlocal cursorline
 
   " Add header
-  call setline(1, 'Search Results for: "' . l:query . '" (' . len(l:results) . ' matches)')
+  call setline(1, a:title . ' (' . len(l:results) . ' matches)')
   call setline(2, repeat('=', 80))
 
   let l:line = 3
@@ -182,15 +212,33 @@ function! noti#Search(query)
 
     for match in result.matches
       if match.context == 'title'
-        call setline(l:line, '    • in title: ' . match.line)
+        let l:prefix = '    • in title: '
       elseif match.context == 'tag'
-        call setline(l:line, '    • in tag: ' . match.line)
+        let l:prefix = '    • in tag: '
       else
-        call setline(l:line, '    • line ' . match.line_number . ': ' . match.line)
+        let l:prefix = '    • line ' . match.line_number
+        if !empty(get(match, 'section', ''))
+          let l:prefix .= ' (' . match.section . ')'
+        endif
+        if get(match, 'code', 0)
+          let l:prefix .= ' [' . (empty(get(match, 'lang', '')) ? 'code' : match.lang) . ']'
+        endif
+        let l:prefix .= ': '
       endif
+      call setline(l:line, l:prefix . match.snippet)
+
+      " Highlight exactly what matched
+      for range in match.highlights
+        call matchaddpos('Search', [[l:line, strlen(l:prefix) + range.start + 1, range.end - range.start]])
+      endfor
       let l:line += 1
     endfor
 
+    if get(result, 'more', 0) > 0
+      call setline(l:line, '    +' . result.more . ' more')
+      let l:line += 1
+    endif
+
     call setline(l:line, '')
     let l:line += 1
   endfor
@@ -359,6 +407,20 @@ function! noti#Folders()
     let l:line += 1
   endfor
 
+  " Saved searches are listed as virtual folders
+  let l:saved = system('noti saved list --json')
+  if v:shell_error == 0
+    let l:saved = json_decode(l:saved)
+    if !empty(l:saved)
+      call setline(l:line, ['', 'Saved searches'])
+      let l:line += 2
+      for search in l:saved
+        call setline(l:line, printf('@%-39s %s', search.name, search.query))
+        let l:line += 1
+      endfor
+    endif
+  endif
+
   setlocal nomodifiable
   setlocal readonly
 
@@ -375,6 +437,12 @@ function! s:FilterByFolder()
   endif
 
   let l:line = getline('.')
+  if l:line =~ '^@'
+    close
+    call noti#SavedSearch(matchstr(l:line, '^@\zs\S\+'))
+    return
+  endif
+
   let l:folder = matchstr(l:line, '^[a-zA-Z0-9/_-]\+')
 
   if !empty(l:folder)
@@ -427,6 +495,61 @@ function! noti#ListByFolder(folder)
   let b:noti_notes = l:notes
 endfunction
 
+" List notes related to the current note
+function! noti#Related()
+  if !s:CheckNotiCLI()
+    return
+  endif
+
+  let l:slug = s:CurrentSlug()
+  if empty(l:slug)
+    echoerr 'Current buffer is not a note in ' . g:noti_notes_dir
+    return
+  endif
+
+  let l:output = system('noti related ' . shellescape(l:slug) . ' --json')
+  if v:shell_error != 0
+    echoerr 'Failed to find related notes: ' . l:output
+    return
+  endif
+
+  let l:related = json_decode(l:output)
+  if empty(l:related)
+    echo 'No related notes found'
+    return
+  endif
+
+  new
+  setlocal buftype=nofile
+  setlocal bufhidden=wipe
+  setlocal noswapfile
+  setlocal nowrap
+  setlocal cursorline
+
+  call setline(1, 'Notes related to ' . l:slug . ' (' . len(l:related) . ' total)')
+  call setline(2, repeat('=', 80))
+
+  let l:line = 3
+  let l:notes = []
+  for item in l:related
+    call setline(l:line, printf('%.3f  %s  (%s)', item.score, item.note.title, join(item.shared_terms, ', ')))
+    let l:line += 1
+    call setline(l:line, '  ' . item.note.slug)
+    let l:line += 1
+    call setline(l:line, '')
+    let l:line += 1
+    call add(l:notes, item.note)
+  endfor
+
+  setlocal nomodifiable
+  setlocal readonly
+
+  nnoremap <buffer> <CR> :call <SID>OpenNoteFromList()<CR>
+  nnoremap <buffer> q :close<CR>
+
+  let b:noti_notes = l:notes
+endfunction
+
 " Git operations
 function! noti#GitStatus()
   if !s:CheckNotiCLI()
@@ -469,15 +592,167 @@ function! noti#GitSync(...)
   echo l:output
 endfunction
 
+" Lint notes and load the problems into the quickfix list
+function! noti#Lint(...)
+  if !s:CheckNotiCLI()
+    return
+  endif
+
+  let l:cmd = 'noti lint --quickfix'
+  if a:0 > 0 && a:1 ==# 'fix'
+    let l:cmd .= ' --fix'
+  endif
+
+  let l:output = system(l:cmd)
+
+  let l:efm = &errorformat
+  let &errorformat = '%f:%l:%c: %m'
+  cgetexpr l:output
+  let &errorformat = l:efm
+
+  if empty(getqflist())
+    cclose
+    echo 'No problems found'
+  else
+    copen
+  endif
+endfunction
+
+" Slug of the note in the current buffer, or '' if it is not a note
+function! s:CurrentSlug()
+  let l:dir = fnamemodify(expand(g:noti_notes_dir), ':p')
+  let l:file = expand('%:p')
+  if l:file !~# '\.md$' || stridx(l:file, l:dir) != 0
+    return ''
+  endif
+  return fnamemodify(l:file[len(l:dir):], ':r')
+endfunction
+
+" Show (or hide) who last changed each part of the current note
+function! noti#Blame()
+  if !s:CheckNotiCLI()
+    return
+  endif
+
+  if get(b:, 'noti_blame_shown', 0)
+    call s:ClearBlame()
+    return
+  endif
+
+  let l:slug = s:CurrentSlug()
+  if empty(l:slug)
+    echoerr 'Current buffer is not a note in ' . g:noti_notes_dir
+    return
+  endif
+
+  let l:output = system('noti blame ' . shellescape(l:slug) . ' --json')
+  if v:shell_error != 0
+    echoerr 'Blame failed: ' . l:output
+    return
+  endif
+
+  let l:result = json_decode(l:output)
+
+  for hunk in l:result.hunks
+    if hunk.uncommitted
+      let l:text = '  not committed yet'
+    else
+      let l:text = printf('  %s, %s • %s', hunk.author, strpart(hunk.date, 0, 10), hunk.summary)
+    endif
+    call s:AddVirtualText(hunk.file_start_line, l:text)
+  endfor
+
+  let b:noti_blame_shown = 1
+endfunction
+
+" Add end-of-line virtual text using extmarks (Neovim) or text properties (Vim 9)
+function! s:AddVirtualText(lnum, text)
+  if has('nvim')
+    let l:ns = nvim_create_namespace('noti_blame')
+    call nvim_buf_set_extmark(0, l:ns, a:lnum - 1, 0, {'virt_text': [[a:text, 'Comment']]})
+  elseif has('textprop') && has('patch-9.0.0067')
+    if empty(prop_type_get('noti_blame', {'bufnr': bufnr('%')}))
+      call prop_type_add('noti_blame', {'bufnr': bufnr('%'), 'highlight': 'Comment'})
+    endif
+    call prop_add(a:lnum, 0, {'type': 'noti_blame', 'text': a:text, 'text_align': 'after'})
+  else
+    echomsg a:lnum . ':' . a:text
+  endif
+endfunction
+
+function! s:ClearBlame()
+  if has('nvim')
+    call nvim_buf_clear_namespace(0, nvim_create_namespace('noti_blame'), 0, -1)
+  elseif has('textprop') && has('patch-9.0.0067')
+    call prop_remove({'type': 'noti_blame', 'bufnr': bufnr('%'), 'all': 1})
+  endif
+  let b:noti_blame_shown = 0
+endfunction
+
+"Complete note names after [[ with slugs, titles, and aliases
+function! noti#Complete(findstart, base)
+  if a:findstart
+    let l:line = strpart(getline('.'), 0, col('.') - 1)
+    let l:start = strridx(l:line, '[[')
+    if l:start < 0 || stridx(l:line, ']]', l:start) >= 0
+      return -3
+    endif
+    return l:start + 2
+  endif
+
+  if !executable('noti')
+    return []
+  endif
+
+  let l:output = system('noti list --json')
+  if v:shell_error != 0
+    return []
+  endif
+
+  let l:notes = json_decode(l:output)
+  if type(l:notes) != v:t_list
+    return []
+  endif
+
+  let l:base = tolower(a:base)
+  let l:items = []
+
+  for note in l:notes
+    let l:names = [[note.slug, 's'], [note.title, 't']]
+    for alias in get(note, 'aliases', [])
+      call add(l:names, [alias, 'a'])
+    endfor
+
+    for [name, kind] in l:names
+      if !empty(name) && stridx(tolower(name), l:base) >= 0
+        call add(l:items, {'word': name, 'kind': kind, 'menu': note.slug, 'info': note.title})
+      endif
+    endfor
+  endfor
+
+  return l:items
+endfunction
+
+if g:noti_complete
+  augroup noti_complete
+    autocmd!
+    execute 'autocmd BufRead,BufNewFile ' . fnameescape(fnamemodify(expand(g:noti_notes_dir), ':p')) . '*.md setlocal completefunc=noti#Complete'
+  augroup END
+endif
+
 " Commands
 command! -nargs=? NotiNew call noti#New(<f-args>)
 command! -nargs=? NotiList call noti#List(<f-args>)
 command! -nargs=? NotiSearch call noti#Search(<q-args>)
 command! NotiTags call noti#Tags()
 command! NotiFolders call noti#Folders()
+command! -nargs=1 -complete=customlist,s:CompleteSaved NotiSaved call noti#SavedSearch(<q-args>)
 command! NotiGitStatus call noti#GitStatus()
 command! -nargs=? NotiGitCommit call noti#GitCommit(<f-args>)
 command! -nargs=? NotiGitSync call noti#GitSync(<f-args>)
+command! NotiBlame call noti#Blame()
+command! NotiRelated call noti#Related()
+command! -nargs=? NotiLint call noti#Lint(<f-args>)
 
 " Default keymappings (can be disabled by setting g:noti_no_default_mappings = 1)
 if !get(g:, 'noti_no_default_mappings', 0)